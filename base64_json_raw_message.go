@@ -0,0 +1,47 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+// Base64JSONRawMessage is a JSONRawMessage which is stored base64-encoded in SQL
+// while marshaling to and from JSON as-is.
+type Base64JSONRawMessage JSONRawMessage
+
+// Scan implements the Scanner interface.
+func (m *Base64JSONRawMessage) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		*m = nil
+		return nil
+	}
+	raw := scanBytes(value)
+	b, err := base64.StdEncoding.DecodeString(string(raw))
+	if err != nil {
+		return reportDecodeError(scanError("Base64JSONRawMessage", value, errors.Wrap(err, "invalid base64")), raw)
+	}
+	*m = b
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (m Base64JSONRawMessage) Value() (driver.Value, error) {
+	v := JSONRawMessage(m).orNull()
+	return base64.StdEncoding.EncodeToString(v), nil
+}
+
+// MarshalJSON returns m as the JSON encoding of m.
+func (m Base64JSONRawMessage) MarshalJSON() ([]byte, error) {
+	return JSONRawMessage(m).MarshalJSON()
+}
+
+// UnmarshalJSON sets *m to a copy of data.
+func (m *Base64JSONRawMessage) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("types.Base64JSONRawMessage: UnmarshalJSON on nil pointer")
+	}
+	return (*JSONRawMessage)(m).UnmarshalJSON(data)
+}
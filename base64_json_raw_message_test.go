@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64JSONRawMessage(t *testing.T) {
+	m := Base64JSONRawMessage(`{"a":1}`)
+
+	v, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "eyJhIjoxfQ==", v)
+
+	var scanned Base64JSONRawMessage
+	require.NoError(t, scanned.Scan([]byte(v.(string))))
+	assert.Equal(t, m, scanned)
+	require.NoError(t, scanned.Scan(v))
+	assert.Equal(t, m, scanned)
+
+	out, err := json.Marshal(struct{ M Base64JSONRawMessage }{M: scanned})
+	require.NoError(t, err)
+	assert.Equal(t, `{"M":{"a":1}}`, string(out))
+
+	var decoded struct{ M Base64JSONRawMessage }
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, m, decoded.M)
+
+	err = scanned.Scan("not base64!")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "base64")
+}
@@ -0,0 +1,151 @@
+package types
+
+// DeepCopier is implemented by types which can return an independent copy of themselves.
+// Every value type of this package implements it, as asserted below; option,
+// error and helper types such as DecodeOptions or Registry do not.
+type DeepCopier[T any] interface {
+	DeepCopy() T
+}
+
+var (
+	_ DeepCopier[NullString]           = NullString("")
+	_ DeepCopier[NullInt64]            = NullInt64{}
+	_ DeepCopier[NullTime]             = NullTime{}
+	_ DeepCopier[JSONRawMessage]       = JSONRawMessage(nil)
+	_ DeepCopier[NullJSONRawMessage]   = NullJSONRawMessage(nil)
+	_ DeepCopier[Base64JSONRawMessage] = Base64JSONRawMessage(nil)
+	_ DeepCopier[NullEnum[string]]     = NullEnum[string]{}
+	_ DeepCopier[NullTimeOfDay]        = NullTimeOfDay{}
+	_ DeepCopier[NullMoney]            = NullMoney{}
+	_ DeepCopier[NullBytes]            = NullBytes(nil)
+	_ DeepCopier[HStore]               = HStore(nil)
+
+	_ DeepCopier[ValidatedJSONRawMessage[StrictJSON]] = ValidatedJSONRawMessage[StrictJSON](nil)
+	_ DeepCopier[EagerJSON[any]]                      = EagerJSON[any]{}
+	_ DeepCopier[NullPtr[any]]                        = NullPtr[any]{}
+)
+
+// VersionedJSONRawMessage has no schema to instantiate it with here, so it is
+// asserted for every S.
+func _[S JSONSchema]() {
+	var _ DeepCopier[VersionedJSONRawMessage[S]] = VersionedJSONRawMessage[S](nil)
+}
+
+// deepCopyValue returns v.DeepCopy() if v is a DeepCopier and v otherwise.
+func deepCopyValue[T any](v T) T {
+	if c, ok := any(v).(DeepCopier[T]); ok {
+		return c.DeepCopy()
+	}
+	return v
+}
+
+// DeepCopy returns a copy of ns.
+func (ns NullString) DeepCopy() NullString {
+	return ns
+}
+
+// DeepCopy returns a copy of ns.
+func (ns NullInt64) DeepCopy() NullInt64 {
+	return ns
+}
+
+// DeepCopy returns a copy of ns.
+func (ns NullTime) DeepCopy() NullTime {
+	return ns
+}
+
+// DeepCopy returns a copy of ne.
+func (ne NullEnum[T]) DeepCopy() NullEnum[T] {
+	return ne
+}
+
+// DeepCopy returns a copy of t.
+func (t NullTimeOfDay) DeepCopy() NullTimeOfDay {
+	return t
+}
+
+// DeepCopy returns a copy of m.
+func (m NullMoney) DeepCopy() NullMoney {
+	return m
+}
+
+// DeepCopy returns a copy of m which does not share its underlying bytes.
+func (m JSONRawMessage) DeepCopy() JSONRawMessage {
+	if m == nil {
+		return nil
+	}
+	return append(JSONRawMessage{}, m...)
+}
+
+// DeepCopy returns a copy of m which does not share its underlying bytes.
+func (m NullJSONRawMessage) DeepCopy() NullJSONRawMessage {
+	if m == nil {
+		return nil
+	}
+	return append(NullJSONRawMessage{}, m...)
+}
+
+// DeepCopy returns a copy of m which does not share its underlying bytes.
+func (m Base64JSONRawMessage) DeepCopy() Base64JSONRawMessage {
+	if m == nil {
+		return nil
+	}
+	return append(Base64JSONRawMessage{}, m...)
+}
+
+// DeepCopy returns a copy of m which does not share its underlying bytes.
+func (m ValidatedJSONRawMessage[V]) DeepCopy() ValidatedJSONRawMessage[V] {
+	if m == nil {
+		return nil
+	}
+	return append(ValidatedJSONRawMessage[V]{}, m...)
+}
+
+// DeepCopy returns a copy of m which does not share its underlying bytes.
+func (m VersionedJSONRawMessage[S]) DeepCopy() VersionedJSONRawMessage[S] {
+	if m == nil {
+		return nil
+	}
+	return append(VersionedJSONRawMessage[S]{}, m...)
+}
+
+// DeepCopy returns a copy of b which does not share its underlying bytes.
+func (b NullBytes) DeepCopy() NullBytes {
+	if b == nil {
+		return nil
+	}
+	return append(NullBytes{}, b...)
+}
+
+// DeepCopy returns a copy of h which does not share its map or values.
+func (h HStore) DeepCopy() HStore {
+	if h == nil {
+		return nil
+	}
+	c := make(HStore, len(h))
+	for k, v := range h {
+		if v != nil {
+			v2 := *v
+			v = &v2
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// DeepCopy returns a copy of e whose Raw does not share its underlying bytes.
+// Val is copied with its DeepCopy method if it has one, and by assignment
+// otherwise.
+func (e EagerJSON[T]) DeepCopy() EagerJSON[T] {
+	return EagerJSON[T]{Raw: e.Raw.DeepCopy(), Val: deepCopyValue(e.Val)}
+}
+
+// DeepCopy returns a copy of n whose P points to a copy of *P. The pointee is
+// copied with its DeepCopy method if it has one, and by assignment otherwise.
+func (n NullPtr[T]) DeepCopy() NullPtr[T] {
+	if n.P == nil {
+		return NullPtr[T]{}
+	}
+	v := deepCopyValue(*n.P)
+	return NullPtr[T]{P: &v}
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepCopy(t *testing.T) {
+	t.Run("type=raw", func(t *testing.T) {
+		raw := JSONRawMessage(`{"a":1}`)
+		rawCopy := raw.DeepCopy()
+		rawCopy[0] = '['
+		assert.Equal(t, `{"a":1}`, string(raw))
+
+		null := NullJSONRawMessage(`{"a":1}`)
+		nullCopy := null.DeepCopy()
+		nullCopy[0] = '['
+		assert.Equal(t, `{"a":1}`, string(null))
+
+		b64 := Base64JSONRawMessage(`{"a":1}`)
+		b64Copy := b64.DeepCopy()
+		b64Copy[0] = '['
+		assert.Equal(t, `{"a":1}`, string(b64))
+
+		validated := ValidatedJSONRawMessage[StrictJSON](`{"a":1}`)
+		validatedCopy := validated.DeepCopy()
+		validatedCopy[0] = '['
+		assert.Equal(t, `{"a":1}`, string(validated))
+
+		versioned := VersionedJSONRawMessage[testTitleSchema](`{"_v":1}`)
+		versionedCopy := versioned.DeepCopy()
+		versionedCopy[0] = '['
+		assert.Equal(t, `{"_v":1}`, string(versioned))
+
+		assert.Nil(t, JSONRawMessage(nil).DeepCopy())
+		assert.Nil(t, NullJSONRawMessage(nil).DeepCopy())
+		assert.Nil(t, Base64JSONRawMessage(nil).DeepCopy())
+		assert.Nil(t, ValidatedJSONRawMessage[StrictJSON](nil).DeepCopy())
+		assert.Nil(t, VersionedJSONRawMessage[testTitleSchema](nil).DeepCopy())
+	})
+
+	t.Run("type=value", func(t *testing.T) {
+		s := NullString("foo")
+		sCopy := s.DeepCopy()
+		sCopy = "bar"
+		assert.Equal(t, NullString("foo"), s)
+		assert.Equal(t, NullString("bar"), sCopy)
+
+		i := NullInt64{Int64: 1, Valid: true}
+		iCopy := i.DeepCopy()
+		iCopy.Int64 = 2
+		assert.Equal(t, int64(1), i.Int64)
+
+		now := time.Now()
+		nt := NullTime(now)
+		ntCopy := nt.DeepCopy()
+		ntCopy = NullTime{}
+		assert.Equal(t, NullTime(now), nt)
+		assert.True(t, ntCopy.IsZero())
+
+		e := NullEnum[string]{Val: "a", Valid: true}
+		eCopy := e.DeepCopy()
+		eCopy.Val = "b"
+		assert.Equal(t, "a", e.Val)
+
+		money := NullMoney{Amount: 100, Currency: "EUR", Valid: true}
+		moneyCopy := money.DeepCopy()
+		moneyCopy.Amount = 200
+		assert.Equal(t, int64(100), money.Amount)
+	})
+
+	t.Run("type=eager", func(t *testing.T) {
+		e := EagerJSON[JSONRawMessage]{Raw: JSONRawMessage(`[1]`), Val: JSONRawMessage(`[1]`)}
+		eCopy := e.DeepCopy()
+		eCopy.Raw[0], eCopy.Val[0] = '{', '{'
+		assert.Equal(t, EagerJSON[JSONRawMessage]{Raw: JSONRawMessage(`[1]`), Val: JSONRawMessage(`[1]`)}, e)
+
+		m := EagerJSON[map[string]int]{Raw: JSONRawMessage(`{"a":1}`), Val: map[string]int{"a": 1}}
+		mCopy := m.DeepCopy()
+		mCopy.Raw[0] = '['
+		assert.Equal(t, `{"a":1}`, string(m.Raw))
+	})
+
+	t.Run("type=ptr", func(t *testing.T) {
+		v := 1
+		p := NewNullPtr(&v)
+		pCopy := p.DeepCopy()
+		*pCopy.P = 2
+		assert.Equal(t, 1, v)
+
+		raw := JSONRawMessage(`[1]`)
+		r := NewNullPtr(&raw)
+		rCopy := r.DeepCopy()
+		(*rCopy.P)[0] = '{'
+		assert.Equal(t, `[1]`, string(raw))
+
+		assert.Nil(t, NullPtr[int]{}.DeepCopy().P)
+	})
+}
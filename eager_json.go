@@ -0,0 +1,61 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// EagerJSON keeps both the raw JSON and its decoded form. Scan and UnmarshalJSON
+// populate Raw and decode it into Val in one step, so that reads do not need to
+// decode again. Value and MarshalJSON encode Val, which is the source of truth
+// when writing.
+type EagerJSON[T any] struct {
+	Raw JSONRawMessage
+	Val T
+}
+
+func (e *EagerJSON[T]) decode(raw []byte) error {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return errors.Wrapf(err, "unable to decode into %T", v)
+	}
+	*e = EagerJSON[T]{Raw: raw, Val: v}
+	return nil
+}
+
+// Scan implements the Scanner interface. SQL NULL is decoded as JSON null.
+func (e *EagerJSON[T]) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = jsonNull
+	}
+	raw := scanBytes(value)
+	if err := e.decode(raw); err != nil {
+		return reportDecodeError(scanError("EagerJSON", value, err), raw)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (e EagerJSON[T]) Value() (driver.Value, error) {
+	return MarshalJSONValue(e.Val)
+}
+
+// MarshalJSON returns the JSON encoding of e.Val.
+func (e EagerJSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Val)
+}
+
+// UnmarshalJSON sets e.Raw to a copy of data and decodes it into e.Val.
+func (e *EagerJSON[T]) UnmarshalJSON(data []byte) error {
+	if e == nil {
+		return errors.New("types.EagerJSON: UnmarshalJSON on nil pointer")
+	}
+	raw := append([]byte{}, data...)
+	if err := e.decode(raw); err != nil {
+		return reportDecodeError(errors.Wrap(err, "types.EagerJSON"), raw)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEagerJSON(t *testing.T) {
+	type doc struct {
+		Name string `json:"name"`
+	}
+
+	var e EagerJSON[doc]
+	require.NoError(t, e.Scan([]byte(`{"name": "foo"}`)))
+	assert.Equal(t, `{"name": "foo"}`, string(e.Raw))
+	assert.Equal(t, doc{Name: "foo"}, e.Val)
+
+	e.Val.Name = "bar"
+	v, err := e.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"bar"}`, v)
+
+	require.Error(t, e.Scan(`{"name":1}`))
+	assert.Equal(t, "bar", e.Val.Name)
+
+	require.NoError(t, e.Scan(nil))
+	assert.Equal(t, `null`, string(e.Raw))
+	assert.Equal(t, doc{}, e.Val)
+
+	var wrapper struct {
+		E EagerJSON[[]int] `json:"e"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"e":[1,2]}`), &wrapper))
+	assert.Equal(t, `[1,2]`, string(wrapper.E.Raw))
+	assert.Equal(t, []int{1, 2}, wrapper.E.Val)
+
+	out, err := json.Marshal(wrapper)
+	require.NoError(t, err)
+	assert.Equal(t, `{"e":[1,2]}`, string(out))
+}
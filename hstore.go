@@ -0,0 +1,195 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HStore represents a PostgreSQL hstore column. A nil map is NULL and a nil
+// value within the map is an hstore NULL. In JSON, it is encoded as an object
+// whose NULL values are JSON null.
+type HStore map[string]*string
+
+// Scan implements the Scanner interface. It parses the hstore text format, for
+// example `"a"=>"1", "b"=>NULL`.
+func (h *HStore) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		*h = nil
+		return nil
+	}
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return reportDecodeError(scanError("HStore", value, errors.New("unsupported type")), scanBytes(value))
+	}
+	parsed, err := parseHStore(s)
+	if err != nil {
+		return reportDecodeError(scanError("HStore", value, err), []byte(s))
+	}
+	*h = parsed
+	return nil
+}
+
+// Value implements the driver Valuer interface. Keys are written in sorted
+// order so that the output is deterministic.
+func (h HStore) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeHStoreString(&b, k)
+		b.WriteString("=>")
+		if v := h[k]; v == nil {
+			b.WriteString("NULL")
+		} else {
+			writeHStoreString(&b, *v)
+		}
+	}
+	return b.String(), nil
+}
+
+// MarshalJSON encodes h as a JSON object, or null if h is nil.
+func (h HStore) MarshalJSON() ([]byte, error) {
+	if h == nil {
+		return []byte(jsonNull), nil
+	}
+	return json.Marshal(map[string]*string(h))
+}
+
+// UnmarshalJSON sets *h to the JSON object in data, whose values must be
+// strings or null. JSON null sets *h to nil.
+func (h *HStore) UnmarshalJSON(data []byte) error {
+	if h == nil {
+		return errors.New("types.HStore: UnmarshalJSON on nil pointer")
+	}
+	var v map[string]*string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+	*h = v
+	return nil
+}
+
+func writeHStoreString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+}
+
+// parseHStore parses the hstore text format. Keys and values are either
+// double-quoted with backslash escapes or bare words; a bare NULL value is an
+// hstore NULL.
+func parseHStore(s string) (HStore, error) {
+	p := hstoreParser{s: s}
+	h := HStore{}
+	if p.skipSpace(); p.done() {
+		return h, nil
+	}
+	for {
+		key, _, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "=>") {
+			return nil, errors.Errorf("expected \"=>\" after hstore key at offset %d", p.pos)
+		}
+		p.pos += 2
+		p.skipSpace()
+		val, quoted, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if !quoted && strings.EqualFold(val, "NULL") {
+			h[key] = nil
+		} else {
+			h[key] = &val
+		}
+		p.skipSpace()
+		if p.done() {
+			return h, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, errors.Errorf("expected \",\" between hstore pairs at offset %d", p.pos)
+		}
+		p.pos++
+		if p.skipSpace(); p.done() {
+			return nil, errors.Errorf("expected hstore pair after \",\" at offset %d", p.pos)
+		}
+	}
+}
+
+type hstoreParser struct {
+	s   string
+	pos int
+}
+
+func (p *hstoreParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *hstoreParser) skipSpace() {
+	for !p.done() && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// token reads a quoted or bare string and reports whether it was quoted.
+func (p *hstoreParser) token() (string, bool, error) {
+	if p.done() {
+		return "", false, errors.New("unexpected end of hstore input")
+	}
+	if p.s[p.pos] != '"' {
+		start := p.pos
+		for !p.done() && strings.IndexByte(" \t\n\r,=\"", p.s[p.pos]) < 0 {
+			p.pos++
+		}
+		if start == p.pos {
+			return "", false, errors.Errorf("unexpected %q in hstore input at offset %d", p.s[p.pos], p.pos)
+		}
+		return p.s[start:p.pos], false, nil
+	}
+	p.pos++
+	var b strings.Builder
+	for !p.done() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if p.done() {
+				return "", false, errors.New("unexpected end of hstore input")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case '"':
+			return b.String(), true, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false, errors.New("unterminated quoted string in hstore input")
+}
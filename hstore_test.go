@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hstoreString(s string) *string {
+	return &s
+}
+
+func TestHStoreScan(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		in     string
+		expect HStore
+		json   string
+	}{
+		{name: "empty", in: ``, expect: HStore{}, json: `{}`},
+		{name: "whitespace", in: `  `, expect: HStore{}, json: `{}`},
+		{name: "pairs", in: `"a"=>"1", "b"=>"2"`, expect: HStore{"a": hstoreString("1"), "b": hstoreString("2")}, json: `{"a":"1","b":"2"}`},
+		{name: "null", in: `"a"=>NULL, "b"=>"NULL"`, expect: HStore{"a": nil, "b": hstoreString("NULL")}, json: `{"a":null,"b":"NULL"}`},
+		{name: "quoting", in: `"a \"b\""=>"c\\d", "e,f"=>"=>"`, expect: HStore{`a "b"`: hstoreString(`c\d`), "e,f": hstoreString("=>")}, json: `{"a \"b\"":"c\\d","e,f":"=>"}`},
+		{name: "bare", in: `a=>1,b => null`, expect: HStore{"a": hstoreString("1"), "b": nil}, json: `{"a":"1","b":null}`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			var h HStore
+			require.NoError(t, h.Scan([]byte(tc.in)))
+			assert.Equal(t, tc.expect, h)
+
+			out, err := json.Marshal(h)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.json, string(out))
+
+			v, err := h.Value()
+			require.NoError(t, err)
+			var again HStore
+			require.NoError(t, again.Scan(v))
+			assert.Equal(t, h, again)
+		})
+	}
+
+	for _, in := range []string{`"a"`, `"a"=>`, `"a"=>"b`, `"a"=>"b" "c"=>"d"`, `=>"b"`, `"a"=>"1",`, `"a"=>"1", `, `,`} {
+		var h HStore
+		assert.Error(t, h.Scan(in), "%s", in)
+	}
+}
+
+func TestHStoreValue(t *testing.T) {
+	v, err := HStore(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = HStore{"b": nil, "a": hstoreString(`x"y`)}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `"a"=>"x\"y", "b"=>NULL`, v)
+
+	var h HStore
+	require.NoError(t, h.Scan(nil))
+	assert.Nil(t, h)
+	require.NoError(t, json.Unmarshal([]byte(`{"a":null}`), &h))
+	assert.Equal(t, HStore{"a": nil}, h)
+}
@@ -0,0 +1,317 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) document to m. Either all
+// operations are applied or, if one of them fails, m is left unchanged.
+//
+// The patched document is re-encoded, so object keys are sorted and
+// insignificant whitespace is removed.
+func (m *JSONRawMessage) ApplyPatch(patch JSONRawMessage) error {
+	var ops []jsonPatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return errors.Wrap(err, "types: unable to decode JSON patch")
+	}
+
+	raw := []byte(*m)
+	if len(raw) == 0 {
+		raw = []byte(jsonNull)
+	}
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		if doc, err = applyPatchOperation(doc, op); err != nil {
+			return errors.Wrapf(err, "types: unable to apply JSON patch operation %d (%s)", i, op.Op)
+		}
+	}
+
+	out, err := encodeJSON(doc)
+	if err != nil {
+		return err
+	}
+	*m = out
+	return nil
+}
+
+func applyPatchOperation(doc interface{}, op jsonPatchOperation) (interface{}, error) {
+	if op.Path == nil {
+		return nil, errors.New(`missing "path"`)
+	}
+	path, err := parseJSONPointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New(`missing "value"`)
+		}
+		if value, err = decodeJSON(op.Value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, errors.New(`missing "from"`)
+		}
+		from, err := parseJSONPointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = getJSONPointer(doc, from); err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			value = copyJSON(value)
+			break
+		}
+		if *op.Path == *op.From {
+			return doc, nil
+		}
+		if strings.HasPrefix(*op.Path, *op.From+"/") {
+			return nil, errors.Errorf("can not move %q into its own child %q", *op.From, *op.Path)
+		}
+		if doc, err = removeJSONPointer(doc, from); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add", "move", "copy":
+		return addJSONPointer(doc, path, value)
+	case "remove":
+		return removeJSONPointer(doc, path)
+	case "replace":
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, err = removeJSONPointer(doc, path); err != nil {
+			return nil, err
+		}
+		return addJSONPointer(doc, path, value)
+	case "test":
+		actual, err := getJSONPointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalJSON(actual, value) {
+			return nil, errors.Errorf("test failed for path %q", *op.Path)
+		}
+		return doc, nil
+	}
+	return nil, errors.Errorf("unknown operation %q", op.Op)
+}
+
+// parseJSONPointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, errors.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// jsonArrayIndex parses token as an index into an array of length n. If
+// allowEnd is true, the index may be n or "-" to address the end of the array.
+func jsonArrayIndex(token string, n int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return n, nil
+	}
+	// RFC 6901 only allows "0" or digits without a leading zero or sign.
+	if token == "" || strings.Trim(token, "0123456789") != "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, errors.Errorf("invalid array index %q", token)
+	}
+	if i > n || (i == n && !allowEnd) {
+		return 0, errors.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
+
+func getJSONPointer(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[token]
+			if !ok {
+				return nil, errors.Errorf("key %q not found", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(d), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = d[i]
+		default:
+			return nil, errors.Errorf("can not resolve %q in a scalar value", token)
+		}
+	}
+	return doc, nil
+}
+
+// mutateJSONPointer calls fn with the container addressed by all but the last
+// token of path and returns doc with the container replaced by fn's result.
+func mutateJSONPointer(doc interface{}, path []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		child, ok := d[path[0]]
+		if !ok {
+			return nil, errors.Errorf("key %q not found", path[0])
+		}
+		child, err := mutateJSONPointer(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		d[path[0]] = child
+		return d, nil
+	case []interface{}:
+		i, err := jsonArrayIndex(path[0], len(d), false)
+		if err != nil {
+			return nil, err
+		}
+		if d[i], err = mutateJSONPointer(d[i], path[1:], fn); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	return nil, errors.Errorf("can not resolve %q in a scalar value", path[0])
+}
+
+func addJSONPointer(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return mutateJSONPointer(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, errors.Errorf("can not add %q to a scalar value", token)
+	})
+}
+
+func removeJSONPointer(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, errors.New("can not remove the document root")
+	}
+	return mutateJSONPointer(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[token]; !ok {
+				return nil, errors.Errorf("key %q not found", token)
+			}
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, errors.Errorf("can not remove %q from a scalar value", token)
+	})
+}
+
+// copyJSON returns a deep copy of a value produced by decodeJSON.
+func copyJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = copyJSON(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = copyJSON(e)
+		}
+		return c
+	}
+	return v
+}
+
+// equalJSON reports whether two values produced by decodeJSON are equal,
+// comparing numbers by their numeric value.
+func equalJSON(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if a == b {
+			return true
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
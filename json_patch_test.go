@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRawMessageApplyPatch(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		doc    string
+		patch  string
+		expect string
+	}{
+		{name: "add key", doc: `{"a":1}`, patch: `[{"op":"add","path":"/b","value":[1]}]`, expect: `{"a":1,"b":[1]}`},
+		{name: "add array element", doc: `{"a":[1,3]}`, patch: `[{"op":"add","path":"/a/1","value":2}]`, expect: `{"a":[1,2,3]}`},
+		{name: "add array end", doc: `{"a":[1]}`, patch: `[{"op":"add","path":"/a/-","value":2}]`, expect: `{"a":[1,2]}`},
+		{name: "add root", doc: `{"a":1}`, patch: `[{"op":"add","path":"","value":[]}]`, expect: `[]`},
+		{name: "remove", doc: `{"a":1,"b":[1,2]}`, patch: `[{"op":"remove","path":"/a"},{"op":"remove","path":"/b/0"}]`, expect: `{"b":[2]}`},
+		{name: "replace root", doc: `{"a":1}`, patch: `[{"op":"replace","path":"","value":[1,{"b":2}]}]`, expect: `[1,{"b":2}]`},
+		{name: "replace", doc: `{"a":{"b":1}}`, patch: `[{"op":"replace","path":"/a/b","value":"x"}]`, expect: `{"a":{"b":"x"}}`},
+		{name: "move", doc: `{"a":{"b":1},"c":[]}`, patch: `[{"op":"move","from":"/a/b","path":"/c/0"}]`, expect: `{"a":{},"c":[1]}`},
+		{name: "copy", doc: `{"a":{"b":1}}`, patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/d","value":2}]`, expect: `{"a":{"b":1},"c":{"b":1,"d":2}}`},
+		{name: "test", doc: `{"a":[1.0,"x"]}`, patch: `[{"op":"test","path":"/a","value":[1,"x"]}]`, expect: `{"a":[1.0,"x"]}`},
+		{name: "escaped pointer", doc: `{"a/b":1,"c~d":2}`, patch: `[{"op":"remove","path":"/a~1b"},{"op":"remove","path":"/c~0d"}]`, expect: `{}`},
+		{name: "empty document", patch: `[{"op":"add","path":"","value":{"a":1}}]`, expect: `{"a":1}`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			m := JSONRawMessage(tc.doc)
+			require.NoError(t, m.ApplyPatch(JSONRawMessage(tc.patch)))
+			assert.JSONEq(t, tc.expect, string(m))
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		patch string
+	}{
+		{name: "failing test", patch: `[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`},
+		{name: "invalid pointer", patch: `[{"op":"add","path":"a","value":2}]`},
+		{name: "missing key", patch: `[{"op":"remove","path":"/x"}]`},
+		{name: "missing parent", patch: `[{"op":"add","path":"/x/y","value":1}]`},
+		{name: "array out of bounds", patch: `[{"op":"replace","path":"/c/5","value":1}]`},
+		{name: "move into child", patch: `[{"op":"move","from":"/c","path":"/c/0"}]`},
+		{name: "missing value", patch: `[{"op":"add","path":"/b"}]`},
+		{name: "unknown operation", patch: `[{"op":"merge","path":"/b"}]`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			m := JSONRawMessage(`{"a":1,"c":[]}`)
+			require.Error(t, m.ApplyPatch(JSONRawMessage(tc.patch)))
+			assert.Equal(t, `{"a":1,"c":[]}`, string(m))
+		})
+	}
+	for _, path := range []string{"/a/+1", "/a/01", "/a/-0", "/a/-1", "/a/ 1", "/a/1e0", "/a/"} {
+		m := JSONRawMessage(`{"a":[1,2,3]}`)
+		err := m.ApplyPatch(JSONRawMessage(`[{"op":"remove","path":"` + path + `"}]`))
+		require.Error(t, err, "%s", path)
+		assert.Contains(t, err.Error(), "invalid array index")
+		assert.Equal(t, `{"a":[1,2,3]}`, string(m))
+	}
+}
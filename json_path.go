@@ -0,0 +1,119 @@
+package types
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPathSegment is either an object key, an array index, or a wildcard.
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Select returns all values in m matching path, which uses a small subset of
+// JSONPath: the root "$" followed by any number of ".key", "[N]", and "[*]"
+// segments, e.g. "$.items[*].name". It returns an empty slice if nothing
+// matches and an error if path is malformed.
+func (m JSONRawMessage) Select(path string) ([]JSONRawMessage, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []JSONRawMessage{m}
+	if len(m) == 0 {
+		current = []JSONRawMessage{JSONRawMessage(jsonNull)}
+	}
+	for _, segment := range segments {
+		next := []JSONRawMessage{}
+		for _, value := range current {
+			matches, err := selectJSONPathSegment(value, segment)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, matches...)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func selectJSONPathSegment(value JSONRawMessage, segment jsonPathSegment) ([]JSONRawMessage, error) {
+	trimmed := strings.TrimSpace(string(value))
+	if segment.isIndex || segment.wildcard {
+		if !strings.HasPrefix(trimmed, "[") {
+			return nil, nil
+		}
+		var elements []JSONRawMessage
+		if err := json.Unmarshal(value, &elements); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if segment.wildcard {
+			return elements, nil
+		}
+		if segment.index >= len(elements) {
+			return nil, nil
+		}
+		return elements[segment.index : segment.index+1], nil
+	}
+
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, nil
+	}
+	var fields map[string]JSONRawMessage
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if v, ok := fields[segment.key]; ok {
+		return []JSONRawMessage{v}, nil
+	}
+	return nil, nil
+}
+
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.Errorf("types: JSONPath %q must start with $", path)
+	}
+
+	var segments []jsonPathSegment
+	for rest := path[1:]; len(rest) > 0; {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, errors.Errorf("types: JSONPath %q contains an empty key", path)
+			}
+			segments = append(segments, jsonPathSegment{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.Errorf("types: JSONPath %q contains an unterminated [", path)
+			}
+			inner := rest[1:end]
+			if inner == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			} else {
+				i, err := strconv.Atoi(inner)
+				if err != nil || i < 0 {
+					return nil, errors.Errorf("types: JSONPath %q contains an invalid index [%s]", path, inner)
+				}
+				segments = append(segments, jsonPathSegment{index: i, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, errors.Errorf("types: JSONPath %q is malformed near %q", path, rest)
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRawMessageSelect(t *testing.T) {
+	m := JSONRawMessage(`{"a":{"b":"c"},"arr":[{"n":1},{"n":2},{"x":3}]}`)
+
+	for _, tc := range []struct {
+		path   string
+		expect []string
+	}{
+		{path: "$", expect: []string{string(m)}},
+		{path: "$.a.b", expect: []string{`"c"`}},
+		{path: "$.arr[0]", expect: []string{`{"n":1}`}},
+		{path: "$.arr[*].n", expect: []string{`1`, `2`}},
+		{path: "$.arr[9]", expect: []string{}},
+		{path: "$.missing.b", expect: []string{}},
+		{path: "$.a[0]", expect: []string{}},
+	} {
+		t.Run("path="+tc.path, func(t *testing.T) {
+			actual, err := m.Select(tc.path)
+			require.NoError(t, err)
+			strs := []string{}
+			for _, v := range actual {
+				strs = append(strs, string(v))
+			}
+			assert.Equal(t, tc.expect, strs)
+		})
+	}
+
+	for _, path := range []string{"a.b", "$..a", "$.arr[", "$.arr[x]", "$.arr[-1]", "$a"} {
+		t.Run("malformed="+path, func(t *testing.T) {
+			_, err := m.Select(path)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,787 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Walk calls fn for every scalar value (string, number, boolean, or null) in m,
+// passing the value's JSON Pointer (RFC 6901) path. Objects are visited in
+// document order. An empty or whitespace-only m is treated as null. If fn
+// returns an error, Walk stops and returns that error.
+func (m JSONRawMessage) Walk(fn func(path string, value JSONRawMessage) error) error {
+	if isJSONNull(m) {
+		return fn("", JSONRawMessage(jsonNull))
+	}
+	if !json.Valid(m) {
+		return errors.New("types.JSONRawMessage: Walk on invalid JSON")
+	}
+	return walkJSON(bytes.TrimSpace(m), "", fn)
+}
+
+func walkJSON(raw []byte, path string, fn func(path string, value JSONRawMessage) error) error {
+	switch raw[0] {
+	case '{', '[':
+	default:
+		return fn(path, raw)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return errors.WithStack(err)
+	}
+	for i := 0; dec.More(); i++ {
+		key := strconv.Itoa(i)
+		if raw[0] == '{' {
+			t, err := dec.Token()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			key = jsonPointerEscaper.Replace(t.(string))
+		}
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := walkJSON(v, path+"/"+key, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeJSON decodes raw into a generic value, preserving numbers as json.Number.
+func decodeJSON(raw []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("types: unexpected data after top-level JSON value")
+	}
+	return v, nil
+}
+
+// encodeJSON encodes a value produced by decodeJSON without escaping HTML characters.
+func encodeJSON(v interface{}) (JSONRawMessage, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// AppendTo appends the output of MarshalJSON to dst and returns the extended
+// buffer.
+func (m JSONRawMessage) AppendTo(dst []byte) []byte {
+	raw, _ := m.MarshalJSON()
+	return append(dst, raw...)
+}
+
+// WriteTo implements io.WriterTo by writing the output of MarshalJSON to w
+// without copying it.
+func (m JSONRawMessage) WriteTo(w io.Writer) (int64, error) {
+	raw, _ := m.MarshalJSON()
+	n, err := w.Write(raw)
+	return int64(n), errors.WithStack(err)
+}
+
+// AsJSONString encodes m as a JSON string, e.g. {"a":1} becomes "{\"a\":1}".
+// An empty m is treated as null. It returns an error if m is not valid JSON.
+func (m JSONRawMessage) AsJSONString() (JSONRawMessage, error) {
+	raw := m.orNull()
+	if !json.Valid(raw) {
+		return nil, errors.New("types.JSONRawMessage: AsJSONString on invalid JSON")
+	}
+	return encodeJSON(string(raw))
+}
+
+// FromJSONString is the inverse of AsJSONString. It returns an error if m is
+// not a JSON string or if the string does not contain valid JSON.
+func (m JSONRawMessage) FromJSONString() (JSONRawMessage, error) {
+	var s *string
+	if err := json.Unmarshal(m, &s); err != nil || s == nil {
+		return nil, errors.New("types.JSONRawMessage: FromJSONString on a value which is not a JSON string")
+	}
+	if !json.Valid([]byte(*s)) {
+		return nil, errors.New("types.JSONRawMessage: FromJSONString on a string which does not contain valid JSON")
+	}
+	return JSONRawMessage(*s), nil
+}
+
+// SortArray returns a copy of m, which must be an array of objects, sorted by
+// the value of the top-level key byKey. All values of byKey must either be
+// strings or numbers. The sort is stable and elements are kept byte-for-byte.
+func (m JSONRawMessage) SortArray(byKey string, asc bool) (JSONRawMessage, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(m, &elements); err != nil || elements == nil {
+		return nil, errors.New("types.JSONRawMessage: SortArray on a value which is not an array")
+	}
+
+	keys := make([]interface{}, len(elements))
+	for i, element := range elements {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(element, &fields); err != nil || fields == nil {
+			return nil, errors.Errorf("types.JSONRawMessage: SortArray element %d is not an object", i)
+		}
+		raw, ok := fields[byKey]
+		if !ok {
+			return nil, errors.Errorf("types.JSONRawMessage: SortArray element %d is missing key %q", i, byKey)
+		}
+		key, err := decodeJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case string:
+			keys[i] = k
+		case json.Number:
+			if keys[i], err = k.Float64(); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		default:
+			return nil, errors.Errorf("types.JSONRawMessage: SortArray element %d has a key %q which is neither a string nor a number", i, byKey)
+		}
+		if i > 0 && reflect.TypeOf(keys[i]) != reflect.TypeOf(keys[0]) {
+			return nil, errors.Errorf("types.JSONRawMessage: SortArray elements have keys %q of different types", byKey)
+		}
+	}
+
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := keys[order[i]], keys[order[j]]
+		if !asc {
+			a, b = b, a
+		}
+		if s, ok := a.(string); ok {
+			return s < b.(string)
+		}
+		return a.(float64) < b.(float64)
+	})
+
+	out := JSONRawMessage{'['}
+	for i, idx := range order {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, elements[idx]...)
+	}
+	return append(out, ']'), nil
+}
+
+// MergeInto decodes m onto dst, which must be a non-nil pointer, updating only
+// the fields whose keys are present in m. Fields absent from m keep their value,
+// nested structs and maps are merged recursively, and arrays and slices are
+// replaced. A present key with a null value resets pointer, map, slice, and
+// interface fields to nil and leaves other fields unchanged. If m is empty or
+// null, dst is left untouched.
+func (m JSONRawMessage) MergeInto(dst interface{}) error {
+	if isJSONNull(m) {
+		return nil
+	}
+	return errors.WithStack(json.Unmarshal(m, dst))
+}
+
+// Flatten flattens m into a map from dotted keys to scalar values. Object keys
+// and array indices are joined with dots, e.g. {"a":{"b":1,"c":[true]}} becomes
+// {"a.b": 1, "a.c.0": true}. Empty objects and arrays are kept as values of
+// their key. A scalar root is returned under the empty key. Numbers are
+// returned as json.Number to preserve their precision. Keys are not escaped,
+// so an error is returned if two values flatten to the same key, as in
+// {"a.b":1,"a":{"b":2}}.
+func (m JSONRawMessage) Flatten() (map[string]interface{}, error) {
+	raw := m.orNull()
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	if err := flattenJSON(result, "", doc); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func flattenJSON(result map[string]interface{}, prefix string, v interface{}) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	set := func(value interface{}) error {
+		if _, ok := result[prefix]; ok {
+			return errors.Errorf("types.JSONRawMessage: Flatten produces the key %q more than once", prefix)
+		}
+		result[prefix] = value
+		return nil
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return set(v)
+		}
+		for key, value := range v {
+			if err := flattenJSON(result, join(key), value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return set(v)
+		}
+		for i, value := range v {
+			if err := flattenJSON(result, join(strconv.Itoa(i)), value); err != nil {
+				return err
+			}
+		}
+	default:
+		return set(v)
+	}
+	return nil
+}
+
+// Preview returns the compacted form of m for use in logs. If it is longer than
+// maxBytes, it is cut to at most maxBytes bytes, without splitting a UTF-8
+// encoded rune, and "…" is appended. Invalid JSON is previewed as-is. The
+// result is not meant to be parsed.
+func (m JSONRawMessage) Preview(maxBytes int) string {
+	raw := m.orNull()
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err == nil {
+		raw = b.Bytes()
+	}
+	if len(raw) <= maxBytes {
+		return string(raw)
+	}
+
+	end := maxBytes
+	if end < 0 {
+		end = 0
+	}
+	for end > 0 && !utf8.RuneStart(raw[end]) {
+		end--
+	}
+	return string(raw[:end]) + "…"
+}
+
+// GetKeyFold returns the value of the top-level key of m which equals key under
+// Unicode case-folding, similar to how encoding/json matches struct fields. An
+// exact match is preferred over case-insensitive ones, and if several keys
+// match equally well, the last one wins as it would when decoding. It returns
+// false if no key matches and an error if m is not an object.
+func (m JSONRawMessage) GetKeyFold(key string) (JSONRawMessage, bool, error) {
+	raw := bytes.TrimSpace(m)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, false, errors.New("types.JSONRawMessage: GetKeyFold on a value which is not an object")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	var exact, folded JSONRawMessage
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, false, errors.WithStack(err)
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, false, errors.WithStack(err)
+		}
+
+		if k := t.(string); k == key {
+			exact = JSONRawMessage(v)
+		} else if strings.EqualFold(k, key) {
+			folded = JSONRawMessage(v)
+		}
+	}
+
+	switch {
+	case exact != nil:
+		return exact, true, nil
+	case folded != nil:
+		return folded, true, nil
+	}
+	return nil, false, nil
+}
+
+// RemoveNulls returns a copy of m in which object entries whose value is null
+// are removed recursively. Null elements of arrays are kept, as removing them
+// would shift the indices of the following elements. The result is re-encoded,
+// so object keys are sorted and insignificant whitespace is removed.
+func (m JSONRawMessage) RemoveNulls() (JSONRawMessage, error) {
+	return m.Minify(MinifyOptions{RemoveNulls: true})
+}
+
+// Pick returns a new object containing only the top-level keys of m which are
+// named in keys. Keys which m does not contain are skipped. The result is
+// re-encoded with sorted keys, and an error is returned if m is not an object.
+func (m JSONRawMessage) Pick(keys ...string) (JSONRawMessage, error) {
+	raw := bytes.TrimSpace(m)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, errors.New("types.JSONRawMessage: Pick on a value which is not an object")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	picked := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		if v, ok := fields[key]; ok {
+			picked[key] = v
+		}
+	}
+	return encodeJSON(picked)
+}
+
+// Keys returns the top-level keys of m in document order without decoding the
+// values. A key occurring more than once is listed once, at the position of its
+// first occurrence. An error is returned if m is not an object.
+func (m JSONRawMessage) Keys() ([]string, error) {
+	raw := bytes.TrimSpace(m)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, errors.New("types.JSONRawMessage: Keys on a value which is not an object")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	keys := []string{}
+	seen := map[string]bool{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		key := t.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("types.JSONRawMessage: Keys on a value with trailing data")
+	}
+	return keys, nil
+}
+
+// Contains reports whether sub is contained in m following the semantics of the
+// PostgreSQL jsonb @> operator: an object contains another if it has all of
+// its keys with values which contain the other's, an array contains another
+// if every element of the other is contained in some element of the array, and
+// scalars contain equal scalars. As with jsonb, an array at the top level also
+// contains a scalar which is one of its elements. Empty messages are treated
+// as null.
+func (m JSONRawMessage) Contains(sub JSONRawMessage) (bool, error) {
+	raw := m.orNull()
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return false, err
+	}
+	raw = sub.orNull()
+	other, err := decodeJSON(raw)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := doc.([]interface{}); ok {
+		switch other.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			other = []interface{}{other}
+		}
+	}
+	return containsJSON(doc, other), nil
+}
+
+func containsJSON(doc, sub interface{}) bool {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		sub, ok := sub.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range sub {
+			w, ok := doc[k]
+			if !ok || !containsJSON(w, v) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		sub, ok := sub.([]interface{})
+		if !ok {
+			return false
+		}
+	outer:
+		for _, v := range sub {
+			for _, w := range doc {
+				if containsJSON(w, v) {
+					continue outer
+				}
+			}
+			return false
+		}
+		return true
+	}
+	return equalJSON(doc, sub)
+}
+
+// ToMap decodes m into a map. It returns nil if m is empty or null, and an
+// error if m is not an object. Numbers are decoded as float64; use ToMapNumber
+// to keep their precision.
+func (m JSONRawMessage) ToMap() (map[string]interface{}, error) {
+	return m.toMap(false)
+}
+
+// ToMapNumber is like ToMap but decodes numbers as json.Number.
+func (m JSONRawMessage) ToMapNumber() (map[string]interface{}, error) {
+	return m.toMap(true)
+}
+
+func (m JSONRawMessage) toMap(useNumber bool) (map[string]interface{}, error) {
+	raw := bytes.TrimSpace(m)
+	if isJSONNull(raw) {
+		return nil, nil
+	}
+	if raw[0] != '{' {
+		return nil, errors.New("types.JSONRawMessage: ToMap on a value which is not an object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if useNumber {
+		dec.UseNumber()
+	}
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("types.JSONRawMessage: ToMap on a value with trailing data")
+	}
+	return v, nil
+}
+
+// MinifyOptions configures JSONRawMessage.Minify.
+type MinifyOptions struct {
+	// RemoveNulls removes object entries whose value is null, as RemoveNulls does.
+	RemoveNulls bool
+
+	// RemoveEmpty removes object entries whose value is an empty object or array,
+	// including ones which only became empty by removing their entries.
+	RemoveEmpty bool
+}
+
+// Minify returns m without insignificant whitespace and with the entries
+// selected by opts removed recursively. Array elements are never removed, as
+// that would shift the indices of the following elements. If any removal is
+// enabled, the result is re-encoded with sorted keys; otherwise the key order
+// of m is kept. An empty m is treated as null.
+func (m JSONRawMessage) Minify(opts MinifyOptions) (JSONRawMessage, error) {
+	raw := m.orNull()
+	if !opts.RemoveNulls && !opts.RemoveEmpty {
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return b.Bytes(), nil
+	}
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(minifyJSON(doc, opts))
+}
+
+func minifyJSON(v interface{}, opts MinifyOptions) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			value = minifyJSON(value, opts)
+			if (opts.RemoveNulls && value == nil) || (opts.RemoveEmpty && isEmptyJSONContainer(value)) {
+				delete(v, key)
+				continue
+			}
+			v[key] = value
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = minifyJSON(value, opts)
+		}
+	}
+	return v
+}
+
+func isEmptyJSONContainer(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// RenameKey returns a copy of m in which the top-level key from is renamed to
+// to. The order of keys and the encoding of values are kept. If m does not
+// contain from, the copy is unchanged. It returns an error if m is not an
+// object or already contains to, as overwriting it would silently lose data.
+func (m JSONRawMessage) RenameKey(from, to string) (JSONRawMessage, error) {
+	raw := bytes.TrimSpace(m)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, errors.New("types.JSONRawMessage: RenameKey on a value which is not an object")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out := []byte{'{'}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		key := t.(string)
+		if key == to && from != to {
+			return nil, errors.Errorf("types.JSONRawMessage: RenameKey target key %q already exists", to)
+		} else if key == from {
+			key = to
+		}
+		encoded, err := encodeJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = append(append(append(out, encoded...), ':'), v...)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("types.JSONRawMessage: RenameKey on a value with trailing data")
+	}
+	return append(out, '}'), nil
+}
+
+// AppendElement appends elem to the array m, which is initialized to [] if it
+// is empty. Only elem is validated; m is not parsed, but its last non-space
+// byte must close the array. An empty elem is appended as null. It returns an
+// error if m is not an array or elem is not valid JSON, leaving m unchanged.
+func (m *JSONRawMessage) AppendElement(elem JSONRawMessage) error {
+	raw := bytes.TrimSpace(*m)
+	if len(raw) == 0 {
+		raw = []byte("[]")
+	}
+	if len(raw) < 2 || raw[0] != '[' || raw[len(raw)-1] != ']' {
+		return errors.New("types.JSONRawMessage: AppendElement on a value which is not an array")
+	}
+	value := elem.orNull()
+	if !json.Valid(value) {
+		return errors.New("types.JSONRawMessage: AppendElement with an element which is not valid JSON")
+	}
+
+	body := raw[:len(raw)-1]
+	out := make(JSONRawMessage, 0, len(raw)+len(value)+1)
+	out = append(out, body...)
+	if len(bytes.TrimSpace(body[1:])) > 0 {
+		out = append(out, ',')
+	}
+	out = append(append(out, value...), ']')
+	*m = out
+	return nil
+}
+
+// StringValue returns the string m encodes. It reports false if m is not a JSON
+// string. Strings without escape sequences are decoded without reflection.
+func (m JSONRawMessage) StringValue() (string, bool) {
+	raw := bytes.TrimSpace(m)
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return "", false
+	}
+	inner := raw[1 : len(raw)-1]
+	if bytes.IndexByte(inner, '\\') < 0 && bytes.IndexByte(inner, '"') < 0 && utf8.Valid(inner) && !hasControlChars(inner) {
+		return string(inner), true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// Int64 returns the integer m encodes. It reports false if m is not a JSON
+// number written as an integer, e.g. 1.0 or 1e3, or does not fit into an int64.
+func (m JSONRawMessage) Int64() (int64, bool) {
+	raw := bytes.TrimSpace(m)
+	if !isJSONNumber(raw) || bytes.ContainsAny(raw, ".eE") {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(string(raw), 10, 64)
+	return i, err == nil
+}
+
+// Float64 returns the number m encodes. It reports false if m is not a JSON
+// number or is out of the range of a float64.
+func (m JSONRawMessage) Float64() (float64, bool) {
+	raw := bytes.TrimSpace(m)
+	if !isJSONNumber(raw) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	return f, err == nil
+}
+
+// Bool returns the boolean m encodes. It reports false if m is neither true
+// nor false.
+func (m JSONRawMessage) Bool() (bool, bool) {
+	switch string(bytes.TrimSpace(m)) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// EnsureObject returns {} if m is empty, whitespace or null, and m otherwise.
+// It does not check that m is an object.
+func (m JSONRawMessage) EnsureObject() JSONRawMessage {
+	if isJSONNull(m) {
+		return JSONRawMessage("{}")
+	}
+	return m
+}
+
+// EnsureArray returns [] if m is empty, whitespace or null, and m otherwise.
+// It does not check that m is an array.
+func (m JSONRawMessage) EnsureArray() JSONRawMessage {
+	if isJSONNull(m) {
+		return JSONRawMessage("[]")
+	}
+	return m
+}
+
+func hasControlChars(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONNumber reports whether b is a number following the JSON grammar, which
+// unlike strconv rejects e.g. a leading +, leading zeros, and hexadecimal.
+func isJSONNumber(b []byte) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && b[i] >= '1' && b[i] <= '9':
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(b) && b[i] == '.' {
+		i++
+		if i == len(b) || b[i] < '0' || b[i] > '9' {
+			return false
+		}
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if i == len(b) || b[i] < '0' || b[i] > '9' {
+			return false
+		}
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+	}
+	return i == len(b)
+}
+
+// MaxDepth returns the maximum nesting depth of objects and arrays in m, which
+// is 0 for scalars and 1 for e.g. {"a":1}. It reads m token by token instead of
+// recursing, so it is safe to use on untrusted input. An empty m is treated as
+// null. It returns an error if m is not valid JSON.
+func (m JSONRawMessage) MaxDepth() (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(m.orNull()))
+	var depth, max, values int
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			if depth++; depth > max {
+				max = depth
+			}
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			if values++; values > 1 {
+				return 0, errors.New("types.JSONRawMessage: MaxDepth on a value with trailing data")
+			}
+		}
+	}
+	if depth != 0 {
+		return 0, errors.WithStack(io.ErrUnexpectedEOF)
+	}
+	return max, nil
+}
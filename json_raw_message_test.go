@@ -0,0 +1,618 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRawMessageWalk(t *testing.T) {
+	m := JSONRawMessage(`{"a":{"b":[1,"two",{"c":null}]},"d/e":true,"f":[],"g~":{}}`)
+
+	var paths []string
+	values := map[string]string{}
+	require.NoError(t, m.Walk(func(path string, value JSONRawMessage) error {
+		paths = append(paths, path)
+		values[path] = string(value)
+		return nil
+	}))
+	assert.Equal(t, []string{"/a/b/0", "/a/b/1", "/a/b/2/c", "/d~1e"}, paths)
+	assert.Equal(t, map[string]string{"/a/b/0": "1", "/a/b/1": `"two"`, "/a/b/2/c": "null", "/d~1e": "true"}, values)
+
+	expected := errors.New("stop")
+	var calls int
+	assert.Equal(t, expected, m.Walk(func(string, JSONRawMessage) error {
+		calls++
+		return expected
+	}))
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, JSONRawMessage(`"scalar"`).Walk(func(path string, value JSONRawMessage) error {
+		assert.Equal(t, "", path)
+		assert.Equal(t, `"scalar"`, string(value))
+		return nil
+	}))
+
+	for _, m := range []JSONRawMessage{nil, JSONRawMessage("  ")} {
+		require.NoError(t, m.Walk(func(path string, value JSONRawMessage) error {
+			assert.Equal(t, "", path)
+			assert.Equal(t, "null", string(value))
+			return nil
+		}))
+	}
+
+	require.Error(t, JSONRawMessage(`{"a":`).Walk(func(string, JSONRawMessage) error { return nil }))
+}
+
+func TestJSONRawMessageAppendTo(t *testing.T) {
+	dst := []byte(`[`)
+	dst = JSONRawMessage(`{"a":1}`).AppendTo(dst)
+	dst = append(dst, ',')
+	dst = JSONRawMessage(nil).AppendTo(dst)
+	dst = append(dst, ']')
+	assert.Equal(t, `[{"a":1},null]`, string(dst))
+}
+
+func TestJSONRawMessageWriteTo(t *testing.T) {
+	var b bytes.Buffer
+	var _ io.WriterTo = JSONRawMessage(nil)
+
+	n, err := JSONRawMessage(`{"a":1}`).WriteTo(&b)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, n)
+	n, err = JSONRawMessage(nil).WriteTo(&b)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, n)
+	assert.Equal(t, `{"a":1}null`, b.String())
+}
+
+func BenchmarkJSONRawMessageAppendTo(b *testing.B) {
+	m := JSONRawMessage(`{"foo":"bar","baz":[1,2,3]}`)
+	dst := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = m.AppendTo(dst[:0])
+	}
+}
+
+func BenchmarkJSONRawMessageAppend(b *testing.B) {
+	m := JSONRawMessage(`{"foo":"bar","baz":[1,2,3]}`)
+	dst := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		out, _ := m.MarshalJSON()
+		dst = append(dst[:0], out...)
+	}
+}
+
+func TestJSONRawMessageAsJSONString(t *testing.T) {
+	for _, in := range []string{`{"a":1}`, `"<b>"`, `[1, 2]`, `null`} {
+		t.Run("in="+in, func(t *testing.T) {
+			encoded, err := JSONRawMessage(in).AsJSONString()
+			require.NoError(t, err)
+
+			var s string
+			require.NoError(t, json.Unmarshal(encoded, &s))
+			assert.Equal(t, in, s)
+
+			decoded, err := encoded.FromJSONString()
+			require.NoError(t, err)
+			assert.Equal(t, in, string(decoded))
+		})
+	}
+
+	encoded, err := JSONRawMessage(`{"a":1}`).AsJSONString()
+	require.NoError(t, err)
+	assert.Equal(t, `"{\"a\":1}"`, string(encoded))
+
+	encoded, err = JSONRawMessage(nil).AsJSONString()
+	require.NoError(t, err)
+	assert.Equal(t, `"null"`, string(encoded))
+
+	_, err = JSONRawMessage(`{"a":`).AsJSONString()
+	require.Error(t, err)
+	_, err = JSONRawMessage(`{"a":1}`).FromJSONString()
+	require.Error(t, err)
+	_, err = JSONRawMessage(`"{\"a\":"`).FromJSONString()
+	require.Error(t, err)
+}
+
+func TestJSONRawMessageSortArray(t *testing.T) {
+	m := JSONRawMessage(`[{"name":"b","n":2},{"name":"c","n":10},{"name":"a","n":1.5}]`)
+
+	for _, tc := range []struct {
+		key    string
+		asc    bool
+		expect string
+	}{
+		{key: "name", asc: true, expect: `[{"name":"a","n":1.5},{"name":"b","n":2},{"name":"c","n":10}]`},
+		{key: "name", asc: false, expect: `[{"name":"c","n":10},{"name":"b","n":2},{"name":"a","n":1.5}]`},
+		{key: "n", asc: true, expect: `[{"name":"a","n":1.5},{"name":"b","n":2},{"name":"c","n":10}]`},
+		{key: "n", asc: false, expect: `[{"name":"c","n":10},{"name":"b","n":2},{"name":"a","n":1.5}]`},
+	} {
+		t.Run(fmt.Sprintf("key=%s/asc=%v", tc.key, tc.asc), func(t *testing.T) {
+			actual, err := m.SortArray(tc.key, tc.asc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, string(actual))
+		})
+	}
+
+	for _, in := range []string{`{"name":"a"}`, `[1,2]`, `[{"name":"a"},{"n":1}]`, `[{"name":"a"},{"name":1}]`, `[{"name":true}]`, ``} {
+		t.Run("invalid="+in, func(t *testing.T) {
+			_, err := JSONRawMessage(in).SortArray("name", true)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestJSONRawMessageMergeInto(t *testing.T) {
+	type nested struct {
+		X, Y int
+	}
+	type doc struct {
+		Name   string
+		Count  int
+		Tags   []string
+		Nested nested
+		Ptr    *int
+	}
+	one := 1
+	original := doc{Name: "a", Count: 1, Tags: []string{"x"}, Nested: nested{X: 1, Y: 2}, Ptr: &one}
+
+	actual := original
+	require.NoError(t, JSONRawMessage(`{"Count":2,"Nested":{"Y":3},"Ptr":null}`).MergeInto(&actual))
+	assert.Equal(t, doc{Name: "a", Count: 2, Tags: []string{"x"}, Nested: nested{X: 1, Y: 3}}, actual)
+
+	actual = original
+	require.NoError(t, JSONRawMessage(nil).MergeInto(&actual))
+	require.NoError(t, JSONRawMessage(` null `).MergeInto(&actual))
+	require.NoError(t, NullJSONRawMessage(nil).MergeInto(&actual))
+	assert.Equal(t, original, actual)
+
+	require.NoError(t, NullJSONRawMessage(`{"Name":"b"}`).MergeInto(&actual))
+	assert.Equal(t, "b", actual.Name)
+
+	require.Error(t, JSONRawMessage(`[1]`).MergeInto(&actual))
+}
+
+func TestJSONRawMessageFlatten(t *testing.T) {
+	actual, err := JSONRawMessage(`{"a":{"b":1,"c":[true,{"d":"x"}]},"e":null,"f":{},"g":[]}`).Flatten()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a.b":     json.Number("1"),
+		"a.c.0":   true,
+		"a.c.1.d": "x",
+		"e":       nil,
+		"f":       map[string]interface{}{},
+		"g":       []interface{}{},
+	}, actual)
+
+	actual, err = JSONRawMessage(`"scalar"`).Flatten()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"": "scalar"}, actual)
+
+	_, err = JSONRawMessage(`{"a":`).Flatten()
+	require.Error(t, err)
+
+	// Map iteration order is random, so check each collision repeatedly.
+	for _, in := range []string{`{"a.b":1,"a":{"b":2}}`, `{"a":[1],"a.0":2}`, `{"a.b":{},"a":{"b":{}}}`} {
+		for i := 0; i < 20; i++ {
+			_, err = JSONRawMessage(in).Flatten()
+			require.Error(t, err, "%s", in)
+			assert.Contains(t, err.Error(), "more than once")
+		}
+	}
+}
+
+func TestJSONRawMessagePreview(t *testing.T) {
+	m := JSONRawMessage("{\n  \"name\": \"héllo\"\n}")
+
+	assert.Equal(t, `{"name":"héllo"}`, m.Preview(100))
+	assert.Equal(t, `{"name":"héllo"}`, m.Preview(17))
+	assert.Equal(t, `{"name":"h…`, m.Preview(10))
+	// "é" occupies bytes 10 and 11, so cutting at 11 must not split it.
+	assert.Equal(t, `{"name":"h…`, m.Preview(11))
+	assert.Equal(t, `{"name":"hé…`, m.Preview(12))
+	assert.Equal(t, `…`, m.Preview(0))
+	assert.Equal(t, `null`, JSONRawMessage(nil).Preview(10))
+	assert.Equal(t, `{inval…`, JSONRawMessage(`{invalid`).Preview(6))
+}
+
+func TestJSONRawMessageGetKeyFold(t *testing.T) {
+	for _, tc := range []struct {
+		doc, key, expect string
+		found            bool
+	}{
+		{doc: `{"UserID":1}`, key: "userId", expect: `1`, found: true},
+		{doc: `{"userid":1,"userId":2,"USERID":3}`, key: "userId", expect: `2`, found: true},
+		{doc: `{"userid":1,"USERID":3}`, key: "userId", expect: `3`, found: true},
+		{doc: `{"straße":"x"}`, key: "STRASSE", found: false},
+		{doc: `{"Σ":"x"}`, key: "σ", expect: `"x"`, found: true},
+		{doc: `{"name":"x"}`, key: "userId", found: false},
+	} {
+		t.Run("doc="+tc.doc, func(t *testing.T) {
+			actual, found, err := JSONRawMessage(tc.doc).GetKeyFold(tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expect, string(actual))
+		})
+	}
+
+	_, _, err := JSONRawMessage(`[1]`).GetKeyFold("a")
+	require.Error(t, err)
+	_, _, err = JSONRawMessage(`{"a":`).GetKeyFold("a")
+	require.Error(t, err)
+}
+
+func TestJSONRawMessageRemoveNulls(t *testing.T) {
+	actual, err := JSONRawMessage(`{"a":null,"b":{"c":null,"d":1,"e":{"f":null}},"g":[null,{"h":null,"i":2}],"j":"null"}`).RemoveNulls()
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":{"d":1,"e":{}},"g":[null,{"i":2}],"j":"null"}`, string(actual))
+
+	actual, err = JSONRawMessage(`null`).RemoveNulls()
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(actual))
+
+	_, err = JSONRawMessage(`{"a":`).RemoveNulls()
+	require.Error(t, err)
+	actual, err = JSONRawMessage(nil).RemoveNulls()
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(actual))
+}
+
+func TestJSONRawMessagePick(t *testing.T) {
+	doc := JSONRawMessage(`{"id": 1, "name": "<a>", "secret": "s", "nested": {"x": [1, 2]}}`)
+
+	actual, err := doc.Pick("nested", "id", "name")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"<a>","nested":{"x":[1,2]}}`, string(actual))
+
+	actual, err = doc.Pick("id", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(actual))
+
+	actual, err = doc.Pick()
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(actual))
+
+	for _, in := range []string{`[1]`, `"a"`, ``, `{"a":`} {
+		_, err = JSONRawMessage(in).Pick("a")
+		require.Error(t, err, "%s", in)
+	}
+}
+
+func TestJSONRawMessageContains(t *testing.T) {
+	for _, tc := range []struct {
+		doc, sub string
+		expect   bool
+	}{
+		{doc: `{"a":1,"b":{"c":[1,2],"d":"x"}}`, sub: `{"b":{"d":"x"}}`, expect: true},
+		{doc: `{"a":1,"b":{"c":[1,2],"d":"x"}}`, sub: `{"a":1.0,"b":{"c":[2]}}`, expect: true},
+		{doc: `{"a":1}`, sub: `{}`, expect: true},
+		{doc: `{"a":1}`, sub: `{"a":2}`, expect: false},
+		{doc: `{"a":1}`, sub: `{"b":1}`, expect: false},
+		{doc: `{"a":{"b":1}}`, sub: `{"b":1}`, expect: false},
+		{doc: `[1,2,3]`, sub: `[3,1]`, expect: true},
+		{doc: `[1,2,3]`, sub: `[1,1]`, expect: true},
+		{doc: `[1,2,3]`, sub: `[]`, expect: true},
+		{doc: `[1,2,3]`, sub: `[4]`, expect: false},
+		{doc: `[1,[2,3]]`, sub: `[[3]]`, expect: true},
+		{doc: `[1,[2,3]]`, sub: `[3]`, expect: false},
+		{doc: `[{"a":1,"b":2}]`, sub: `[{"a":1}]`, expect: true},
+		{doc: `["foo","bar"]`, sub: `"foo"`, expect: true},
+		{doc: `["foo","bar"]`, sub: `"baz"`, expect: false},
+		{doc: `{"a":["foo"]}`, sub: `{"a":"foo"}`, expect: false},
+		{doc: `"foo"`, sub: `"foo"`, expect: true},
+		{doc: `"foo"`, sub: `["foo"]`, expect: false},
+		{doc: ``, sub: `null`, expect: true},
+	} {
+		t.Run(fmt.Sprintf("doc=%s/sub=%s", tc.doc, tc.sub), func(t *testing.T) {
+			actual, err := JSONRawMessage(tc.doc).Contains(JSONRawMessage(tc.sub))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, actual)
+		})
+	}
+
+	_, err := JSONRawMessage(`{"a":`).Contains(JSONRawMessage(`{}`))
+	require.Error(t, err)
+	_, err = JSONRawMessage(`{}`).Contains(JSONRawMessage(`{"a":`))
+	require.Error(t, err)
+}
+
+func TestJSONRawMessageToMap(t *testing.T) {
+	for _, in := range []string{``, `null`, ` null `} {
+		actual, err := JSONRawMessage(in).ToMap()
+		require.NoError(t, err)
+		assert.Nil(t, actual)
+	}
+
+	actual, err := JSONRawMessage(`{"a":1,"b":{"c":[true]}}`).ToMap()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": float64(1), "b": map[string]interface{}{"c": []interface{}{true}}}, actual)
+
+	actual, err = JSONRawMessage(`{"a":12345678901234567890}`).ToMapNumber()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": json.Number("12345678901234567890")}, actual)
+
+	for _, in := range []string{`[1]`, `"a"`, `1`, `{"a":`, `{}{}`} {
+		_, err = JSONRawMessage(in).ToMap()
+		require.Error(t, err, "%s", in)
+	}
+}
+
+func TestJSONRawMessageMinify(t *testing.T) {
+	doc := JSONRawMessage(`{ "z": 1, "a": null, "b": {}, "c": [ ], "d": { "e": null }, "f": [ null, {} ] }`)
+	for _, tc := range []struct {
+		opts   MinifyOptions
+		expect string
+	}{
+		{opts: MinifyOptions{}, expect: `{"z":1,"a":null,"b":{},"c":[],"d":{"e":null},"f":[null,{}]}`},
+		{opts: MinifyOptions{RemoveNulls: true}, expect: `{"b":{},"c":[],"d":{},"f":[null,{}],"z":1}`},
+		{opts: MinifyOptions{RemoveEmpty: true}, expect: `{"a":null,"d":{"e":null},"f":[null,{}],"z":1}`},
+		{opts: MinifyOptions{RemoveNulls: true, RemoveEmpty: true}, expect: `{"f":[null,{}],"z":1}`},
+	} {
+		t.Run(fmt.Sprintf("opts=%+v", tc.opts), func(t *testing.T) {
+			actual, err := doc.Minify(tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, string(actual))
+		})
+	}
+
+	actual, err := JSONRawMessage(nil).Minify(MinifyOptions{RemoveEmpty: true})
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(actual))
+	_, err = JSONRawMessage(`{"a":`).Minify(MinifyOptions{})
+	require.Error(t, err)
+	_, err = JSONRawMessage(`{"a":`).Minify(MinifyOptions{RemoveNulls: true})
+	require.Error(t, err)
+}
+
+func TestJSONRawMessageRenameKey(t *testing.T) {
+	actual, err := JSONRawMessage(`{"z":1, "old": {"a": [1, 2]}, "b": "<"}`).RenameKey("old", "new")
+	require.NoError(t, err)
+	assert.Equal(t, `{"z":1,"new":{"a": [1, 2]},"b":"<"}`, string(actual))
+
+	actual, err = JSONRawMessage(`{"a":1}`).RenameKey("missing", "b")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(actual))
+
+	actual, err = JSONRawMessage(`{"a":1}`).RenameKey("a", "a")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(actual))
+
+	_, err = JSONRawMessage(`{"old":1,"new":2}`).RenameKey("old", "new")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"new" already exists`)
+
+	for _, in := range []string{`[1]`, `"a"`, ``, `{"a":`, `{"a":1} garbage`, `{"a":1}{}`} {
+		_, err = JSONRawMessage(in).RenameKey("a", "b")
+		require.Error(t, err, "%s", in)
+	}
+}
+
+func TestJSONRawMessageEmptyJSON(t *testing.T) {
+	type doc struct {
+		Settings JSONRawMessage `json:"settings"`
+	}
+
+	out, err := json.Marshal(doc{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"settings":null}`, string(out))
+
+	JSONRawMessageEmptyJSON = `{}`
+	defer func() { JSONRawMessageEmptyJSON = jsonNull }()
+
+	out, err = json.Marshal(doc{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"settings":{}}`, string(out))
+	out, err = json.Marshal(doc{Settings: JSONRawMessage(`null`)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"settings":null}`, string(out))
+	assert.Equal(t, `[{}]`, string(JSONRawMessage(nil).AppendTo([]byte(`[`)))+`]`)
+
+	v, err := JSONRawMessage(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, `null`, v)
+	flat, err := JSONRawMessage(nil).Flatten()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"": nil}, flat)
+}
+
+func TestJSONRawMessageAppendElement(t *testing.T) {
+	var m JSONRawMessage
+	require.NoError(t, m.AppendElement(JSONRawMessage(`{"a":1}`)))
+	require.NoError(t, m.AppendElement(JSONRawMessage(`"two"`)))
+	require.NoError(t, m.AppendElement(nil))
+	assert.Equal(t, `[{"a":1},"two",null]`, string(m))
+	assert.True(t, json.Valid(m))
+
+	m = JSONRawMessage(` [ 1 ] `)
+	require.NoError(t, m.AppendElement(JSONRawMessage(`2`)))
+	assert.Equal(t, `[ 1 ,2]`, string(m))
+	m = JSONRawMessage(`[ ]`)
+	require.NoError(t, m.AppendElement(JSONRawMessage(`2`)))
+	assert.Equal(t, `[ 2]`, string(m))
+
+	for _, in := range []string{`{}`, `"[]"`, `null`, `[`} {
+		m = JSONRawMessage(in)
+		require.Error(t, m.AppendElement(JSONRawMessage(`1`)), "%s", in)
+		assert.Equal(t, in, string(m))
+	}
+	m = JSONRawMessage(`[1]`)
+	require.Error(t, m.AppendElement(JSONRawMessage(`{`)))
+	assert.Equal(t, `[1]`, string(m))
+}
+
+func TestJSONRawMessageScalars(t *testing.T) {
+	for _, tc := range []struct {
+		in string
+		s  interface{}
+		i  interface{}
+		f  interface{}
+		b  interface{}
+	}{
+		{in: `"abc"`, s: "abc"},
+		{in: ` "ünï" `, s: "ünï"},
+		{in: `"a\"bé\n"`, s: "a\"bé\n"},
+		{in: `""`, s: ""},
+		{in: `42`, i: int64(42), f: float64(42)},
+		{in: `-9223372036854775808`, i: int64(math.MinInt64), f: float64(math.MinInt64)},
+		{in: `9223372036854775808`, f: float64(9223372036854775808)},
+		{in: `1.5`, f: 1.5},
+		{in: `1e3`, f: float64(1000)},
+		{in: `-0`, i: int64(0), f: math.Copysign(0, -1)},
+		{in: `true`, b: true},
+		{in: ` false `, b: false},
+		{in: `null`},
+		{in: ``},
+		{in: `{}`},
+		{in: `[1]`},
+		{in: `+1`},
+		{in: `01`},
+		{in: `0x10`},
+		{in: `1.`},
+		{in: `"abc`},
+		{in: `"a"b"`},
+		{in: `True`},
+		{in: `1e400`},
+	} {
+		t.Run("in="+tc.in, func(t *testing.T) {
+			m := JSONRawMessage(tc.in)
+			s, ok := m.StringValue()
+			assert.Equal(t, tc.s != nil, ok)
+			if ok {
+				assert.Equal(t, tc.s, s)
+			}
+			i, ok := m.Int64()
+			assert.Equal(t, tc.i != nil, ok)
+			if ok {
+				assert.Equal(t, tc.i, i)
+			}
+			f, ok := m.Float64()
+			assert.Equal(t, tc.f != nil, ok)
+			if ok {
+				assert.Equal(t, tc.f, f)
+			}
+			b, ok := m.Bool()
+			assert.Equal(t, tc.b != nil, ok)
+			if ok {
+				assert.Equal(t, tc.b, b)
+			}
+		})
+	}
+}
+
+func TestJSONRawMessageReset(t *testing.T) {
+	m := make(JSONRawMessage, 0, 64)
+	require.NoError(t, m.UnmarshalJSON([]byte(`{"a":1}`)))
+	backing := &m[:1][0]
+
+	m.Reset()
+	assert.Len(t, m, 0)
+	assert.Equal(t, 64, cap(m))
+
+	require.NoError(t, m.Scan([]byte(`[1,2,3]`)))
+	assert.Equal(t, `[1,2,3]`, string(m))
+	assert.Same(t, backing, &m[0])
+
+	m.Reset()
+	require.NoError(t, m.UnmarshalJSON([]byte(`"x"`)))
+	assert.Equal(t, `"x"`, string(m))
+	assert.Same(t, backing, &m[0])
+
+	// Without Reset, Scan does not overwrite bytes which may still be referenced.
+	kept := m
+	require.NoError(t, m.Scan(`"y"`))
+	assert.Equal(t, `"x"`, string(kept))
+	assert.Equal(t, `"y"`, string(m))
+}
+
+func TestJSONRawMessageMaxDepth(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		depth int
+	}{
+		{in: ``, depth: 0},
+		{in: `1`, depth: 0},
+		{in: `{"a":1,"b":"[{"}`, depth: 1},
+		{in: `[]`, depth: 1},
+		{in: `{"a":[1,{"b":[[]]}],"c":{}}`, depth: 5},
+		{in: strings.Repeat(`[`, 1000) + strings.Repeat(`]`, 1000), depth: 1000},
+	} {
+		depth, err := JSONRawMessage(tc.in).MaxDepth()
+		require.NoError(t, err, "%s", tc.in)
+		assert.Equal(t, tc.depth, depth, "%s", tc.in)
+	}
+
+	for _, in := range []string{`{`, `[1]]`, `{}{}`, `1 2`, `{"a"}`} {
+		_, err := JSONRawMessage(in).MaxDepth()
+		require.Error(t, err, "%s", in)
+	}
+}
+
+func TestRawMessageMaxDepth(t *testing.T) {
+	nested := []byte(`{"a":{"b":{"c":1}}}`)
+
+	var m JSONRawMessage
+	require.NoError(t, m.Scan(nested))
+
+	RawMessageMaxDepth = 2
+	defer func() { RawMessageMaxDepth = 0 }()
+
+	require.NoError(t, m.Scan([]byte(`{"a":{"b":1}}`)))
+	err := m.Scan(nested)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrJSONTooDeep))
+	assert.Equal(t, `{"a":{"b":1}}`, string(m))
+
+	var doc struct {
+		Raw  JSONRawMessage
+		Null NullJSONRawMessage
+	}
+	assert.True(t, errors.Is(json.Unmarshal([]byte(`{"Raw":`+string(nested)+`}`), &doc), ErrJSONTooDeep))
+	assert.True(t, errors.Is(json.Unmarshal([]byte(`{"Null":`+string(nested)+`}`), &doc), ErrJSONTooDeep))
+	require.NoError(t, json.Unmarshal([]byte(`{"Raw":[[1]],"Null":null}`), &doc))
+
+	var n NullJSONRawMessage
+	assert.True(t, errors.Is(n.Scan(string(nested)), ErrJSONTooDeep))
+	require.NoError(t, n.Scan(nil))
+}
+
+func TestJSONRawMessageEnsureObjectArray(t *testing.T) {
+	for _, in := range []JSONRawMessage{nil, {}, JSONRawMessage(" \n"), JSONRawMessage("null"), JSONRawMessage(" null ")} {
+		assert.Equal(t, JSONRawMessage(`{}`), in.EnsureObject(), "%q", in)
+		assert.Equal(t, JSONRawMessage(`[]`), in.EnsureArray(), "%q", in)
+	}
+
+	for _, in := range []JSONRawMessage{JSONRawMessage(`{"a":1}`), JSONRawMessage(`[1]`), JSONRawMessage(`"null"`), JSONRawMessage(`0`)} {
+		assert.Same(t, &in[0], &in.EnsureObject()[0], "%s", in)
+		assert.Same(t, &in[0], &in.EnsureArray()[0], "%s", in)
+	}
+}
+
+func TestJSONRawMessageKeys(t *testing.T) {
+	keys, err := JSONRawMessage(` {"z":1,"a":{"y":2,"b":3},"m":[{"n":4}],"é\"":null} `).Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"z", "a", "m", `é"`}, keys)
+
+	keys, err = JSONRawMessage(`{"b":1,"a":2,"b":3}`).Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, keys)
+
+	keys, err = JSONRawMessage(`{}`).Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, keys)
+
+	for _, in := range []string{``, `null`, `[]`, `"a"`, `{"a"}`, `{"a":1`, `{"a":1,}`, `{"a":1}{}`, `{"a":1} x`} {
+		_, err := JSONRawMessage(in).Keys()
+		assert.Error(t, err, "%s", in)
+	}
+}
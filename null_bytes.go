@@ -0,0 +1,64 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// NullBytes represents NULLable binary data, such as a bytea or BLOB column.
+// A nil slice is NULL while an empty, non-nil slice is a valid, empty value.
+// In JSON, it is encoded as a base64 string like []byte, or null.
+type NullBytes []byte
+
+// Scan implements the Scanner interface.
+func (b *NullBytes) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		*b = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		*b = append(NullBytes{}, v...)
+	case string:
+		*b = NullBytes(v)
+	default:
+		return scanError("NullBytes", value, errors.New("unsupported type"))
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (b NullBytes) Value() (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return []byte(b), nil
+}
+
+// MarshalJSON encodes b as a base64 JSON string, or null if b is nil.
+func (b NullBytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte(jsonNull), nil
+	}
+	return json.Marshal([]byte(b))
+}
+
+// UnmarshalJSON sets *b to the bytes of the base64 JSON string in data. JSON
+// null sets *b to nil, and an empty string to an empty, non-nil slice.
+func (b *NullBytes) UnmarshalJSON(data []byte) error {
+	if b == nil {
+		return errors.New("types.NullBytes: UnmarshalJSON on nil pointer")
+	}
+	var v []byte
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+	if v == nil && !isJSONNull(data) {
+		v = []byte{}
+	}
+	*b = v
+	return nil
+}
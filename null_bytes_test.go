@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullBytes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   NullBytes
+		json string
+		sql  interface{}
+	}{
+		{name: "null", in: nil, json: `null`, sql: nil},
+		{name: "empty", in: NullBytes{}, json: `""`, sql: []byte{}},
+		{name: "populated", in: NullBytes{0xde, 0xad, 0xbe, 0xef}, json: `"3q2+7w=="`, sql: []byte{0xde, 0xad, 0xbe, 0xef}},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			out, err := json.Marshal(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(out))
+
+			var decoded NullBytes
+			require.NoError(t, json.Unmarshal(out, &decoded))
+			assert.Equal(t, tc.in, decoded)
+
+			v, err := tc.in.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tc.sql, v)
+
+			scanned := NullBytes("previous")
+			require.NoError(t, scanned.Scan(v))
+			assert.Equal(t, tc.in, scanned)
+		})
+	}
+
+	src := []byte("abc")
+	var b NullBytes
+	require.NoError(t, b.Scan(src))
+	src[0] = 'x'
+	assert.Equal(t, NullBytes("abc"), b)
+
+	require.Error(t, b.Scan(int64(1)))
+}
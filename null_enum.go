@@ -0,0 +1,98 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var enumValues sync.Map
+
+// RegisterEnumValues restricts the values which NullEnum[T].Scan and
+// NullEnum[T].UnmarshalJSON accept to allowed. Without registration, any value is accepted.
+func RegisterEnumValues[T ~string](allowed ...T) {
+	set := make(map[T]struct{}, len(allowed))
+	for _, v := range allowed {
+		set[v] = struct{}{}
+	}
+	enumValues.Store(reflect.TypeOf((*T)(nil)).Elem(), set)
+}
+
+// NullEnum represents a NULLable string enumeration.
+type NullEnum[T ~string] struct {
+	Val   T
+	Valid bool
+}
+
+func (ne NullEnum[T]) validate() error {
+	set, ok := enumValues.Load(reflect.TypeOf(ne.Val))
+	if !ok {
+		return nil
+	}
+	if _, ok := set.(map[T]struct{})[ne.Val]; !ok {
+		return errors.Errorf("value %q is not allowed for %T", string(ne.Val), ne.Val)
+	}
+	return nil
+}
+
+// Scan implements the Scanner interface.
+func (ne *NullEnum[T]) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
+	}
+	var v sql.NullString
+	if err := (&v).Scan(value); err != nil {
+		return scanError("NullEnum", value, err)
+	}
+	n := NullEnum[T]{Val: T(v.String), Valid: v.Valid}
+	if n.Valid {
+		if err := n.validate(); err != nil {
+			return scanError("NullEnum", value, err)
+		}
+	}
+	*ne = n
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (ne NullEnum[T]) Value() (driver.Value, error) {
+	if !ne.Valid {
+		return nil, nil
+	}
+	return string(ne.Val), nil
+}
+
+// MarshalJSON encodes ne as a JSON string, or null if ne is NULL.
+func (ne NullEnum[T]) MarshalJSON() ([]byte, error) {
+	if !ne.Valid {
+		return []byte(jsonNull), nil
+	}
+	return json.Marshal(string(ne.Val))
+}
+
+// UnmarshalJSON sets *ne to the JSON string in data, which must be an allowed
+// value. JSON null sets *ne to NULL.
+func (ne *NullEnum[T]) UnmarshalJSON(data []byte) error {
+	if ne == nil {
+		return errors.New("types.NullEnum: UnmarshalJSON on nil pointer")
+	}
+	var v *string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+	if v == nil {
+		*ne = NullEnum[T]{}
+		return nil
+	}
+	n := NullEnum[T]{Val: T(*v), Valid: true}
+	if err := n.validate(); err != nil {
+		return errors.Wrap(err, "types.NullEnum")
+	}
+	*ne = n
+	return nil
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testColor string
+
+func TestNullEnum(t *testing.T) {
+	RegisterEnumValues[testColor]("red", "green")
+
+	var c NullEnum[testColor]
+	require.NoError(t, json.Unmarshal([]byte(`"red"`), &c))
+	assert.Equal(t, NullEnum[testColor]{Val: "red", Valid: true}, c)
+
+	require.NoError(t, c.Scan("green"))
+	assert.Equal(t, NullEnum[testColor]{Val: "green", Valid: true}, c)
+
+	err := json.Unmarshal([]byte(`"blue"`), &c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"blue" is not allowed`)
+	require.Error(t, c.Scan("blue"))
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &c))
+	assert.False(t, c.Valid)
+	require.NoError(t, c.Scan(nil))
+	assert.False(t, c.Valid)
+
+	v, err := c.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	out, err := json.Marshal(NullEnum[testColor]{Val: "red", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, `"red"`, string(out))
+}
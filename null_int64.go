@@ -0,0 +1,80 @@
+package types
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// NullInt64MarshalString controls whether NullInt64.MarshalJSON emits a quoted
+// string ("42") instead of a JSON number (42).
+var NullInt64MarshalString = false
+
+// NullInt64 represents a NULLable int64 which unmarshals from both JSON numbers
+// and quoted numeric strings.
+type NullInt64 struct {
+	Int64 int64
+	Valid bool
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInt64) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
+	}
+	var v sql.NullInt64
+	if err := (&v).Scan(value); err != nil {
+		return scanError("NullInt64", value, err)
+	}
+	*ns = NullInt64{Int64: v.Int64, Valid: v.Valid}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInt64) Value() (driver.Value, error) {
+	return sql.NullInt64{Int64: ns.Int64, Valid: ns.Valid}.Value()
+}
+
+// MarshalJSON encodes ns as a JSON number, or as a JSON string if
+// NullInt64MarshalString is set. NULL is encoded as null.
+func (ns NullInt64) MarshalJSON() ([]byte, error) {
+	if !ns.Valid {
+		return []byte(jsonNull), nil
+	}
+	if NullInt64MarshalString {
+		return json.Marshal(strconv.FormatInt(ns.Int64, 10))
+	}
+	return json.Marshal(ns.Int64)
+}
+
+// UnmarshalJSON sets *ns to the number encoded in data, which may either be a
+// JSON number or a JSON string containing a number.
+func (ns *NullInt64) UnmarshalJSON(data []byte) error {
+	if ns == nil {
+		return errors.New("types.NullInt64: UnmarshalJSON on nil pointer")
+	}
+	data = bytes.TrimSpace(data)
+	if isJSONNull(data) {
+		*ns = NullInt64{}
+		return nil
+	}
+
+	raw := string(data)
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	i, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return errors.Errorf("types.NullInt64: unable to parse %s as integer: %s", data, err)
+	}
+	*ns = NullInt64{Int64: i, Valid: true}
+	return nil
+}
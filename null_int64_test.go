@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullInt64(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		expect NullInt64
+	}{
+		{in: `42`, expect: NullInt64{Int64: 42, Valid: true}},
+		{in: `"42"`, expect: NullInt64{Int64: 42, Valid: true}},
+		{in: `null`, expect: NullInt64{}},
+	} {
+		t.Run("in="+tc.in, func(t *testing.T) {
+			var actual NullInt64
+			require.NoError(t, json.Unmarshal([]byte(tc.in), &actual))
+			assert.Equal(t, tc.expect, actual)
+		})
+	}
+
+	var actual NullInt64
+	require.Error(t, json.Unmarshal([]byte(`"forty-two"`), &actual))
+
+	out, err := json.Marshal(NullInt64{Int64: 42, Valid: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, `42`, string(out))
+
+	NullInt64MarshalString = true
+	defer func() { NullInt64MarshalString = false }()
+	out, err = json.Marshal(NullInt64{Int64: 42, Valid: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, `"42"`, string(out))
+
+	out, err = json.Marshal(NullInt64{})
+	require.NoError(t, err)
+	assert.EqualValues(t, `null`, string(out))
+}
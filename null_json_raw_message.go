@@ -0,0 +1,88 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MarshalJSONIndent is like MarshalJSON but indents the output as json.Indent
+// does. It is intended for human-readable output such as debug endpoints.
+func (m NullJSONRawMessage) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	if isJSONNull(m) {
+		return []byte(jsonNull), nil
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, m, prefix, indent); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b.Bytes(), nil
+}
+
+// MergeInto works like JSONRawMessage.MergeInto.
+func (m NullJSONRawMessage) MergeInto(dst interface{}) error {
+	return JSONRawMessage(m).MergeInto(dst)
+}
+
+// ScanNoCopy is like Scan but, if value is a []byte or json.RawMessage, makes
+// m share its underlying array instead of copying it. This avoids allocating
+// for large documents, but is only safe if the caller owns value: most drivers
+// reuse the buffer passed to Scan for the next row, which would silently
+// change m. Other values are copied as by Scan.
+func (m *NullJSONRawMessage) ScanNoCopy(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case json.RawMessage:
+		raw = v
+	}
+	if raw == nil {
+		return m.Scan(value)
+	}
+	if err := checkMaxDepth(raw); err != nil {
+		return reportDecodeError(scanError("NullJSONRawMessage", value, err), raw)
+	}
+	*m = raw
+	return nil
+}
+
+// ScanInto decodes m into dst and reports true, unless m is empty or null, in
+// which case dst is left untouched and false is returned.
+//
+//	if present, err := row.Settings.ScanInto(&settings); err != nil {
+//		return err
+//	} else if !present {
+//		settings = defaultSettings
+//	}
+func (m NullJSONRawMessage) ScanInto(dst interface{}) (present bool, err error) {
+	if isJSONNull(m) {
+		return false, nil
+	}
+	if err := json.Unmarshal(m, dst); err != nil {
+		return true, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// SafeString returns m as valid UTF-8 for use in logs, replacing each run of
+// invalid bytes with U+FFFD. If the result is longer than maxBytes, it is cut
+// to at most maxBytes bytes without splitting a rune, and truncated is true. A
+// non-positive maxBytes means no limit. NULL is returned as null.
+func (m NullJSONRawMessage) SafeString(maxBytes int) (s string, truncated bool) {
+	s = string(JSONRawMessage(m).orNull())
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s, false
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end], true
+}
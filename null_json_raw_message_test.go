@@ -0,0 +1,200 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullJSONRawMessageMarshalJSONIndent(t *testing.T) {
+	m := NullJSONRawMessage(`{"a":[1,2]}`)
+
+	compact, err := m.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[1,2]}`, string(compact))
+
+	indented, err := m.MarshalJSONIndent("", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    2\n  ]\n}", string(indented))
+
+	indented, err = NullJSONRawMessage(nil).MarshalJSONIndent("", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(indented))
+
+	indented, err = NullJSONRawMessage(" null ").MarshalJSONIndent("", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(indented))
+
+	_, err = NullJSONRawMessage(`{`).MarshalJSONIndent("", "  ")
+	require.Error(t, err)
+}
+
+func TestNullJSONRawMessageScanBytes(t *testing.T) {
+	type customBytes []byte
+	type customString string
+
+	for _, in := range []interface{}{
+		json.RawMessage(`{"a":1}`),
+		customBytes(`{"a":1}`),
+		customString(`{"a":1}`),
+		[]byte(`{"a":1}`),
+		`{"a":1}`,
+	} {
+		t.Run(fmt.Sprintf("type=%T", in), func(t *testing.T) {
+			var m NullJSONRawMessage
+			require.NoError(t, m.Scan(in))
+			assert.Equal(t, `{"a":1}`, string(m))
+		})
+	}
+
+	src := json.RawMessage(`{"a":1}`)
+	var m NullJSONRawMessage
+	require.NoError(t, m.Scan(src))
+	src[0] = '['
+	assert.Equal(t, `{"a":1}`, string(m))
+
+	require.NoError(t, m.Scan(nil))
+	assert.Equal(t, `null`, string(m))
+}
+
+func TestNullJSONRawMessageStoreAsSQLNull(t *testing.T) {
+	for _, tc := range []struct {
+		storeAsSQLNull bool
+		expect         interface{}
+	}{
+		{storeAsSQLNull: true, expect: nil},
+		{storeAsSQLNull: false, expect: "null"},
+	} {
+		t.Run(fmt.Sprintf("sql_null=%v", tc.storeAsSQLNull), func(t *testing.T) {
+			NullJSONRawMessageStoreAsSQLNull = tc.storeAsSQLNull
+			defer func() { NullJSONRawMessageStoreAsSQLNull = true }()
+
+			for _, m := range []NullJSONRawMessage{nil, {}, NullJSONRawMessage("null")} {
+				v, err := m.Value()
+				require.NoError(t, err)
+				assert.Equal(t, tc.expect, v)
+			}
+
+			v, err := NullJSONRawMessage(`{}`).Value()
+			require.NoError(t, err)
+			assert.Equal(t, `{}`, v)
+		})
+	}
+	for _, m := range []NullJSONRawMessage{nil, {}, NullJSONRawMessage("null"), NullJSONRawMessage(" null\n")} {
+		v, err := m.ValueSQLNull()
+		require.NoError(t, err)
+		assert.Nil(t, v, "%q", m)
+		v, err = m.ValueJSONNull()
+		require.NoError(t, err)
+		assert.Equal(t, "null", v, "%q", m)
+	}
+	v, err := NullJSONRawMessage(`[1]`).ValueJSONNull()
+	require.NoError(t, err)
+	assert.Equal(t, `[1]`, v)
+}
+
+// Padded null used to be stored verbatim as JSON text. It is now treated like
+// null and follows NullJSONRawMessageStoreAsSQLNull.
+func TestNullJSONRawMessageValuePaddedNullIsSQLNull(t *testing.T) {
+	v, err := NullJSONRawMessage(" null ").Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullJSONRawMessageScanNoCopy(t *testing.T) {
+	owned := []byte(`{"a":1}`)
+	var m NullJSONRawMessage
+	require.NoError(t, m.ScanNoCopy(owned))
+	assert.Equal(t, `{"a":1}`, string(m))
+	assert.Same(t, &owned[0], &m[0])
+
+	require.NoError(t, m.ScanNoCopy(json.RawMessage(`[1]`)))
+	assert.Equal(t, `[1]`, string(m))
+	require.NoError(t, m.ScanNoCopy(`"s"`))
+	assert.Equal(t, `"s"`, string(m))
+	require.NoError(t, m.ScanNoCopy(nil))
+	assert.Equal(t, `null`, string(m))
+	require.NoError(t, m.ScanNoCopy([]byte(nil)))
+	assert.Equal(t, `null`, string(m))
+
+	RawMessageMaxDepth = 1
+	defer func() { RawMessageMaxDepth = 0 }()
+	for _, in := range []interface{}{[]byte(`[[[1]]]`), json.RawMessage(`[[[1]]]`), `[[[1]]]`} {
+		err := m.ScanNoCopy(in)
+		require.Error(t, err, "%T", in)
+		assert.True(t, errors.Is(err, ErrJSONTooDeep), "%+v", err)
+		assert.Equal(t, `null`, string(m))
+	}
+	require.NoError(t, m.ScanNoCopy([]byte(`[1]`)))
+}
+
+func BenchmarkNullJSONRawMessageScan(b *testing.B) {
+	raw := bytes.Repeat([]byte(`{"foo":"bar"},`), 1<<12)
+	b.Run("copy", func(b *testing.B) {
+		b.ReportAllocs()
+		var m NullJSONRawMessage
+		for i := 0; i < b.N; i++ {
+			_ = m.Scan(raw)
+		}
+	})
+	b.Run("nocopy", func(b *testing.B) {
+		b.ReportAllocs()
+		var m NullJSONRawMessage
+		for i := 0; i < b.N; i++ {
+			_ = m.ScanNoCopy(raw)
+		}
+	})
+}
+
+func TestNullJSONRawMessageScanInto(t *testing.T) {
+	type settings struct {
+		A int `json:"a"`
+	}
+	for _, in := range []string{``, `null`, ` null `} {
+		dst := settings{A: 7}
+		present, err := NullJSONRawMessage(in).ScanInto(&dst)
+		require.NoError(t, err)
+		assert.False(t, present)
+		assert.Equal(t, settings{A: 7}, dst)
+	}
+
+	var dst settings
+	present, err := NullJSONRawMessage(`{"a":1}`).ScanInto(&dst)
+	require.NoError(t, err)
+	assert.True(t, present)
+	assert.Equal(t, settings{A: 1}, dst)
+
+	present, err = NullJSONRawMessage(`{"a":"x"}`).ScanInto(&dst)
+	require.Error(t, err)
+	assert.True(t, present)
+}
+
+func TestNullJSONRawMessageSafeString(t *testing.T) {
+	for _, tc := range []struct {
+		in        NullJSONRawMessage
+		maxBytes  int
+		expect    string
+		truncated bool
+	}{
+		{in: nil, expect: `null`},
+		{in: NullJSONRawMessage(`{"a":"héllo"}`), expect: `{"a":"héllo"}`},
+		{in: NullJSONRawMessage("\"a\xffb\xc3\x28\""), expect: "\"a�b�(\""},
+		{in: NullJSONRawMessage("\xfe\xff\xfe"), expect: "�"},
+		{in: NullJSONRawMessage(`{"a":"héllo"}`), maxBytes: 9, expect: `{"a":"hé`, truncated: true},
+		{in: NullJSONRawMessage(`{"a":"héllo"}`), maxBytes: 8, expect: `{"a":"h`, truncated: true},
+		{in: NullJSONRawMessage("\xff\xffabc"), maxBytes: 2, expect: "", truncated: true},
+		{in: NullJSONRawMessage("\xff\xffabc"), maxBytes: 4, expect: "�a", truncated: true},
+		{in: NullJSONRawMessage(`[1,2]`), maxBytes: 5, expect: `[1,2]`},
+	} {
+		actual, truncated := tc.in.SafeString(tc.maxBytes)
+		assert.Equal(t, tc.expect, actual, "%q", tc.in)
+		assert.Equal(t, tc.truncated, truncated, "%q", tc.in)
+		assert.True(t, utf8.ValidString(actual), "%q", actual)
+	}
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NullMoneyMinorUnits lists the number of decimal places of the ISO 4217
+// currencies whose minor unit is not a cent. Currencies not present here have
+// two decimal places.
+var NullMoneyMinorUnits = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// NullMoney represents a NULLable amount of money stored as an integer number
+// of minor units, for example cents, so that it is exact without a decimal
+// library. In SQL, only the amount is stored; Scan leaves Currency untouched.
+// In JSON, it is encoded as an object with the amount as a decimal string:
+//
+//	{"amount":"12.34","currency":"USD"}
+type NullMoney struct {
+	Amount   int64
+	Currency string
+	Valid    bool
+}
+
+// Scan implements the Scanner interface. It accepts integers and numeric text
+// holding the amount in minor units.
+func (m *NullMoney) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
+	}
+	var v sql.NullInt64
+	if err := (&v).Scan(value); err != nil {
+		return scanError("NullMoney", value, err)
+	}
+	m.Amount, m.Valid = v.Int64, v.Valid
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (m NullMoney) Value() (driver.Value, error) {
+	return sql.NullInt64{Int64: m.Amount, Valid: m.Valid}.Value()
+}
+
+// String returns the amount followed by the currency, or an empty string if m
+// is not valid.
+func (m NullMoney) String() string {
+	if !m.Valid {
+		return ""
+	}
+	return strings.TrimSpace(formatMoney(m.Amount, m.Currency) + " " + m.Currency)
+}
+
+type nullMoneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes m as an object with the amount as a decimal string, or
+// null if m is NULL.
+func (m NullMoney) MarshalJSON() ([]byte, error) {
+	if !m.Valid {
+		return []byte(jsonNull), nil
+	}
+	amount := strconv.Quote(formatMoney(m.Amount, m.Currency))
+	return json.Marshal(nullMoneyJSON{Amount: json.RawMessage(amount), Currency: m.Currency})
+}
+
+// UnmarshalJSON sets *m to the money encoded in data. The amount may either be
+// a JSON string or a JSON number, but must not have more decimal places than
+// the currency.
+func (m *NullMoney) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("types.NullMoney: UnmarshalJSON on nil pointer")
+	}
+	if isJSONNull(data) {
+		*m = NullMoney{}
+		return nil
+	}
+
+	var v nullMoneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+	raw := string(bytes.TrimSpace(v.Amount))
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(v.Amount, &raw); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	amount, err := parseMoney(raw, v.Currency)
+	if err != nil {
+		return errors.Errorf("types.NullMoney: unable to parse %s as amount: %s", v.Amount, err)
+	}
+	*m = NullMoney{Amount: amount, Currency: v.Currency, Valid: true}
+	return nil
+}
+
+func moneyMinorUnits(currency string) int {
+	if digits, ok := NullMoneyMinorUnits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// formatMoney formats amount minor units of currency as a decimal string.
+func formatMoney(amount int64, currency string) string {
+	digits := moneyMinorUnits(currency)
+	abs := uint64(amount)
+	if amount < 0 {
+		abs = -abs
+	}
+	s := strconv.FormatUint(abs, 10)
+	if digits > 0 {
+		if len(s) <= digits {
+			s = strings.Repeat("0", digits-len(s)+1) + s
+		}
+		s = s[:len(s)-digits] + "." + s[len(s)-digits:]
+	}
+	if amount < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// parseMoney parses the decimal string s as minor units of currency.
+func parseMoney(s, currency string) (int64, error) {
+	digits := moneyMinorUnits(currency)
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+		if frac == "" {
+			return 0, errors.New("missing decimal places")
+		}
+	}
+	if len(frac) > digits {
+		return 0, errors.Errorf("more than %d decimal places", digits)
+	}
+	if strings.TrimLeft(whole, "+-") == "" || strings.ContainsAny(frac, "+-") {
+		return 0, errors.New("invalid syntax")
+	}
+	return strconv.ParseInt(whole+frac+strings.Repeat("0", digits-len(frac)), 10, 64)
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullMoneyJSON(t *testing.T) {
+	for _, tc := range []struct {
+		money  NullMoney
+		expect string
+	}{
+		{money: NullMoney{Amount: 1234, Currency: "USD", Valid: true}, expect: `{"amount":"12.34","currency":"USD"}`},
+		{money: NullMoney{Amount: 5, Currency: "USD", Valid: true}, expect: `{"amount":"0.05","currency":"USD"}`},
+		{money: NullMoney{Amount: -1200, Currency: "EUR", Valid: true}, expect: `{"amount":"-12.00","currency":"EUR"}`},
+		{money: NullMoney{Amount: 1234, Currency: "JPY", Valid: true}, expect: `{"amount":"1234","currency":"JPY"}`},
+		{money: NullMoney{Amount: 1234, Currency: "KWD", Valid: true}, expect: `{"amount":"1.234","currency":"KWD"}`},
+		{money: NullMoney{Amount: math.MinInt64, Currency: "USD", Valid: true}, expect: `{"amount":"-92233720368547758.08","currency":"USD"}`},
+		{money: NullMoney{Amount: 1234, Currency: "USD"}, expect: `null`},
+	} {
+		t.Run("expect="+tc.expect, func(t *testing.T) {
+			out, err := json.Marshal(tc.money)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, string(out))
+
+			var actual NullMoney
+			require.NoError(t, json.Unmarshal(out, &actual))
+			if tc.money.Valid {
+				assert.Equal(t, tc.money, actual)
+			} else {
+				assert.Equal(t, NullMoney{}, actual)
+			}
+		})
+	}
+
+	var actual NullMoney
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":12.3,"currency":"EUR"}`), &actual))
+	assert.Equal(t, NullMoney{Amount: 1230, Currency: "EUR", Valid: true}, actual)
+
+	for _, in := range []string{
+		`{"amount":"12.345","currency":"USD"}`,
+		`{"amount":"1.5","currency":"JPY"}`,
+		`{"amount":"12.","currency":"USD"}`,
+		`{"amount":"-.5","currency":"USD"}`,
+		`{"amount":"1.-5","currency":"USD"}`,
+		`{"amount":"twelve","currency":"USD"}`,
+		`{"amount":"92233720368547758.08","currency":"USD"}`,
+		`"12.34"`,
+	} {
+		assert.Error(t, json.Unmarshal([]byte(in), &actual), "%s", in)
+	}
+}
+
+func TestNullMoneyScan(t *testing.T) {
+	m := NullMoney{Currency: "CHF"}
+	require.NoError(t, m.Scan(int64(1999)))
+	assert.Equal(t, NullMoney{Amount: 1999, Currency: "CHF", Valid: true}, m)
+	assert.Equal(t, "19.99 CHF", m.String())
+
+	require.NoError(t, m.Scan([]byte("-250")))
+	assert.Equal(t, NullMoney{Amount: -250, Currency: "CHF", Valid: true}, m)
+
+	value, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(-250), value)
+
+	require.NoError(t, m.Scan(nil))
+	assert.Equal(t, NullMoney{Currency: "CHF"}, m)
+	assert.Equal(t, "", m.String())
+	value, err = m.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.Error(t, m.Scan("12.34"))
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// NullPtr adapts a pointer field to a nullable JSON column. A nil P is stored
+// as SQL NULL and encoded as JSON null, while a non-nil P is stored and encoded
+// as the JSON encoding of *P.
+//
+//	type User struct {
+//		Address types.NullPtr[Address] `db:"address"`
+//	}
+type NullPtr[T any] struct {
+	P *T
+}
+
+// NewNullPtr returns a NullPtr wrapping p.
+func NewNullPtr[T any](p *T) NullPtr[T] {
+	return NullPtr[T]{P: p}
+}
+
+// Scan implements the Scanner interface. SQL NULL and JSON null set P to nil.
+func (n *NullPtr[T]) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		n.P = nil
+		return nil
+	}
+	raw := scanBytes(value)
+	var p *T
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return reportDecodeError(scanError("NullPtr", value, err), raw)
+	}
+	n.P = p
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullPtr[T]) Value() (driver.Value, error) {
+	return MarshalJSONValue(n.P)
+}
+
+// MarshalJSON encodes the value n.P points to, or null if n.P is nil.
+func (n NullPtr[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.P)
+}
+
+// UnmarshalJSON sets n.P to a new T decoded from data, or to nil if data is
+// JSON null.
+func (n *NullPtr[T]) UnmarshalJSON(data []byte) error {
+	if n == nil {
+		return errors.New("types.NullPtr: UnmarshalJSON on nil pointer")
+	}
+	var p *T
+	if err := json.Unmarshal(data, &p); err != nil {
+		return errors.WithStack(err)
+	}
+	n.P = p
+	return nil
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullPtrAddress struct {
+	City string `json:"city"`
+}
+
+func TestNullPtr(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   NullPtr[nullPtrAddress]
+		json string
+		sql  interface{}
+	}{
+		{name: "nil", in: NullPtr[nullPtrAddress]{}, json: `null`, sql: nil},
+		{name: "set", in: NewNullPtr(&nullPtrAddress{City: "Berlin"}), json: `{"city":"Berlin"}`, sql: `{"city":"Berlin"}`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			out, err := json.Marshal(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(out))
+
+			decoded := NewNullPtr(&nullPtrAddress{City: "previous"})
+			require.NoError(t, json.Unmarshal(out, &decoded))
+			assert.Equal(t, tc.in, decoded)
+
+			v, err := tc.in.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tc.sql, v)
+
+			scanned := NewNullPtr(&nullPtrAddress{City: "previous"})
+			require.NoError(t, scanned.Scan(v))
+			assert.Equal(t, tc.in, scanned)
+		})
+	}
+
+	var n NullPtr[nullPtrAddress]
+	require.NoError(t, n.Scan([]byte(`null`)))
+	assert.Nil(t, n.P)
+	require.Error(t, n.Scan(`{"city":1}`))
+}
@@ -0,0 +1,616 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// NullTimeMin and NullTimeMax bound the values accepted by NullTime.Scan.
+	// A zero bound disables the respective check, which is the default.
+	NullTimeMin, NullTimeMax time.Time
+
+	// NullTimeClamp makes NullTime.Scan clamp out-of-range values to the nearest
+	// bound instead of returning ErrNullTimeOutOfRange.
+	NullTimeClamp = false
+
+	// ErrNullTimeOutOfRange is returned by NullTime.Scan if the scanned value lies
+	// outside of [NullTimeMin, NullTimeMax].
+	ErrNullTimeOutOfRange = errors.New("types.NullTime: time out of range")
+
+	// NullTimeNullStrings lists additional strings, such as "N/A" or "-", which
+	// NullTime.UnmarshalText and NullTime.UnmarshalJSON treat as NULL.
+	NullTimeNullStrings []string
+
+	// NullTimeNullJSONTokens lists JSON tokens besides null and "", such as false
+	// or 0, which NullTime.UnmarshalJSON treats as NULL for loose upstream APIs.
+	// Tokens are compared with the input as-is after trimming whitespace, so 0
+	// does not match 0.0.
+	NullTimeNullJSONTokens []string
+
+	// NullTimeNullAsEmptyString makes NullTime.MarshalJSON encode NULL as the
+	// empty JSON string "" instead of null. NullTime.UnmarshalJSON always decodes
+	// both as NULL.
+	NullTimeNullAsEmptyString = false
+
+	// NullTimeLayouts enables parsing of string and []byte values in NullTime.Scan
+	// using additional layouts. The layouts are tried in order, after time.RFC3339,
+	// and the first one which parses the value wins.
+	NullTimeLayouts []string
+
+	// NullTimeSpreadsheetSerial makes NullTime.Scan interpret float64 values as
+	// spreadsheet serial dates, i.e. fractional days since 1899-12-30 UTC, as
+	// exported by Excel and similar tools.
+	NullTimeSpreadsheetSerial = false
+
+	// NullTimeNullAsEpoch makes NullTime.MarshalJSON encode NULL as the Unix epoch
+	// ("1970-01-01T00:00:00Z") instead of null, and NullTime.UnmarshalJSON decode
+	// the Unix epoch as NULL.
+	NullTimeNullAsEpoch = false
+
+	// NullTimeUnixSeconds makes NullTime.MarshalJSON encode times as a JSON number
+	// of seconds since the Unix epoch including the sub-second fraction, e.g.
+	// 1609459200.123, and NullTime.UnmarshalJSON decode such numbers. NULL is
+	// still encoded as null.
+	NullTimeUnixSeconds = false
+
+	// NullTimeMarshalWholeSeconds makes NullTime.MarshalJSON truncate times to
+	// whole seconds, e.g. 2006-01-02T15:04:05Z, for consumers which reject
+	// fractional seconds. Scanned and decoded values keep their full precision.
+	NullTimeMarshalWholeSeconds = false
+
+	// NullTimeFractionDigits makes NullTime.MarshalJSON always emit this many
+	// fractional second digits, from 0 to 9, padding with zeros or truncating as
+	// needed, e.g. 3 for 2006-01-02T15:04:05.000Z. If negative, which is the
+	// default, trailing zeros are omitted as by time.RFC3339Nano.
+	NullTimeFractionDigits = -1
+
+	// NullTimeAssumeUTC makes NullTime.Scan reinterpret time.Time values from
+	// the driver as UTC, keeping their wall clock, e.g. 12:00 +02:00 becomes
+	// 12:00 UTC. This is for drivers which return TIMESTAMP WITHOUT TIME ZONE
+	// columns in the local time zone although the stored values are UTC.
+	NullTimeAssumeUTC = false
+
+	// NullTimeDurationSinceEpoch makes NullTime.Scan accept strings which are a Go
+	// duration since the Unix epoch as parsed by time.ParseDuration, e.g.
+	// "1609459200s" or "451291h". They are tried before NullTimeLayouts.
+	NullTimeDurationSinceEpoch = false
+
+	// NullTimeISODates makes NullTime.Scan accept the ISO 8601 ordinal dates
+	// 2006-002 and 2006002 and the week dates 2006-W01-2, 2006W012, 2006-W01, and
+	// 2006W01, the latter two meaning the Monday of the week. They denote
+	// midnight of the day and are tried before numeric epoch strings and
+	// NullTimeLayouts.
+	NullTimeISODates = false
+
+	// OnNullTimeParseError, if set, is called with the input whenever NullTime.Scan
+	// fails to parse a string value with any of the supported formats, before the
+	// error is returned. Like OnDecodeError, it can be used to log or sample bad data.
+	OnNullTimeParseError func(raw string)
+
+	// NullTimeHTTPDates makes NullTime.Scan additionally try time.RFC1123 and
+	// time.RFC1123Z, as used by HTTP Date and Last-Modified headers, after NullTimeLayouts.
+	NullTimeHTTPDates = false
+
+	// NullTimeEpochUnit enables scanning integer values in NullTime.Scan as the
+	// number of units, e.g. time.Second or time.Millisecond, since the Unix epoch.
+	// Strings consisting only of digits, optionally preceded by a minus sign, are
+	// scanned the same way instead of being parsed with a layout. If
+	// NullTimeISODates is enabled as well, a string which is a valid ISO 8601
+	// basic ordinal date such as 2006002 is scanned as that date instead.
+	// It is disabled by default.
+	NullTimeEpochUnit time.Duration
+
+	// ErrNullTimeEpochOverflow is returned by NullTime.Scan if an epoch value can
+	// not be represented as int64 nanoseconds since the Unix epoch.
+	ErrNullTimeEpochOverflow = errors.New("types.NullTime: epoch value overflows")
+)
+
+// NullTimeConverter converts a driver-specific value to a time.Time. It returns
+// false if it does not handle value.
+type NullTimeConverter func(value interface{}) (t time.Time, ok bool, err error)
+
+var (
+	nullTimeConvertersMu sync.RWMutex
+	nullTimeConverters   []NullTimeConverter
+)
+
+// RegisterNullTimeConverter registers fn to be consulted by NullTime.Scan for
+// values which it cannot handle itself, such as driver-specific timestamp
+// types. Converters are tried in the order in which they were registered until
+// one returns true.
+func RegisterNullTimeConverter(fn NullTimeConverter) {
+	nullTimeConvertersMu.Lock()
+	defer nullTimeConvertersMu.Unlock()
+	nullTimeConverters = append(nullTimeConverters, fn)
+}
+
+// TimeValidTupleConverter is a NullTimeConverter for drivers which return
+// nullable timestamps as a [2]interface{}{time.Time, bool} tuple of the time and
+// whether it is valid. Invalid tuples are NULL.
+//
+//	types.RegisterNullTimeConverter(types.TimeValidTupleConverter)
+func TimeValidTupleConverter(value interface{}) (time.Time, bool, error) {
+	tuple, ok := value.([2]interface{})
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	t, okTime := tuple[0].(time.Time)
+	valid, okValid := tuple[1].(bool)
+	if !okValid || (valid && !okTime) {
+		return time.Time{}, true, errors.Errorf("tuple %T, %T is not a time.Time and bool", tuple[0], tuple[1])
+	}
+	if !valid {
+		return time.Time{}, true, nil
+	}
+	return t, true, nil
+}
+
+func convertNullTime(value interface{}) (time.Time, bool, error) {
+	nullTimeConvertersMu.RLock()
+	defer nullTimeConvertersMu.RUnlock()
+	for _, fn := range nullTimeConverters {
+		if t, ok, err := fn(value); ok || err != nil {
+			return t, true, err
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+var spreadsheetEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+func isNullTimeJSONToken(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	for _, token := range NullTimeNullJSONTokens {
+		if string(data) == token {
+			return true
+		}
+	}
+	return false
+}
+
+func isNullTimeSentinel(s string) bool {
+	for _, sentinel := range NullTimeNullStrings {
+		if s == sentinel {
+			return true
+		}
+	}
+	return false
+}
+
+// scanTime converts a driver value to a time.Time. For strings parsed with a
+// layout, it also returns that layout.
+func scanTime(value interface{}, loc *time.Location) (time.Time, string, error) {
+	switch v := value.(type) {
+	case string:
+		return scanTimeString(v, loc)
+	case []byte:
+		return scanTimeString(string(v), loc)
+	case time.Time:
+		if NullTimeAssumeUTC {
+			return wallClockUTC(v), "", nil
+		}
+	case float64:
+		if NullTimeSpreadsheetSerial {
+			t, err := spreadsheetSerialTime(v)
+			return t, "", err
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if NullTimeEpochUnit > 0 {
+			t, err := epochTime(value)
+			return t, "", err
+		}
+	}
+	t, err := scanNativeTime(value)
+	if err != nil {
+		if converted, ok, cerr := convertNullTime(value); ok {
+			return converted, "", cerr
+		}
+		// Some driver wrappers return fmt.Stringer values instead of strings.
+		if s, ok := value.(fmt.Stringer); ok {
+			str := s.String()
+			return scanTimeString(str, loc)
+		}
+	}
+	return t, "", err
+}
+
+var (
+	isoOrdinalDate = regexp.MustCompile(`^(\d{4})-?(\d{3})$`)
+	isoWeekDate    = regexp.MustCompile(`^(\d{4})-W(\d{2})(?:-(\d))?$|^(\d{4})W(\d{2})(\d)?$`)
+)
+
+// parseISODate parses an ISO 8601 ordinal or week date in loc. It reports
+// false if s is neither or denotes a day which does not exist.
+func parseISODate(s string, loc *time.Location) (time.Time, bool) {
+	if m := isoOrdinalDate.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		day, _ := strconv.Atoi(m[2])
+		t := time.Date(year, 1, day, 0, 0, 0, 0, loc)
+		return t, day >= 1 && t.Year() == year
+	}
+	if m := isoWeekDate.FindStringSubmatch(s); m != nil {
+		fields := m[1:4]
+		if fields[0] == "" {
+			fields = m[4:7]
+		}
+		year, _ := strconv.Atoi(fields[0])
+		week, _ := strconv.Atoi(fields[1])
+		weekday := 1
+		if fields[2] != "" {
+			weekday, _ = strconv.Atoi(fields[2])
+		}
+		if weekday < 1 || weekday > 7 {
+			return time.Time{}, false
+		}
+		// Week 1 is the week containing January 4th, and weeks start on Monday.
+		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		t := monday.AddDate(0, 0, (week-1)*7+weekday-1)
+		y, w := t.ISOWeek()
+		return t, week >= 1 && y == year && w == week
+	}
+	return time.Time{}, false
+}
+
+// fixedFractionLayout returns an RFC 3339 layout with exactly digits, at most
+// nine, fractional second digits.
+func fixedFractionLayout(digits int) string {
+	if digits == 0 {
+		return time.RFC3339
+	}
+	if digits > 9 {
+		digits = 9
+	}
+	return "2006-01-02T15:04:05." + strings.Repeat("0", digits) + "Z07:00"
+}
+
+// wallClockUTC returns t with its wall clock interpreted in UTC.
+func wallClockUTC(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	return time.Date(y, mo, d, h, mi, s, t.Nanosecond(), time.UTC)
+}
+
+// epochTime converts an integer number of NullTimeEpochUnit since the Unix
+// epoch to a time.Time, guarding against integer overflow.
+func epochTime(value interface{}) (time.Time, error) {
+	var v int64
+	switch i := value.(type) {
+	case int:
+		v = int64(i)
+	case int8:
+		v = int64(i)
+	case int16:
+		v = int64(i)
+	case int32:
+		v = int64(i)
+	case int64:
+		v = i
+	case uint:
+		return epochTime(uint64(i))
+	case uint8:
+		v = int64(i)
+	case uint16:
+		v = int64(i)
+	case uint32:
+		v = int64(i)
+	case uint64:
+		if i > math.MaxInt64 {
+			return time.Time{}, errors.Wrapf(ErrNullTimeEpochOverflow, "%d does not fit into int64", i)
+		}
+		v = int64(i)
+	default:
+		return time.Time{}, errors.Errorf("%T is not an integer", value)
+	}
+
+	unit := int64(NullTimeEpochUnit)
+	if v > math.MaxInt64/unit || v < math.MinInt64/unit {
+		return time.Time{}, errors.Wrapf(ErrNullTimeEpochOverflow, "%d multiplied by %s", v, NullTimeEpochUnit)
+	}
+	return time.Unix(0, v*unit).UTC(), nil
+}
+
+// isEpochString reports whether s is an optionally negative decimal integer.
+func isEpochString(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// Spreadsheet serial dates of the years 1 to 9999.
+const (
+	minSpreadsheetSerial = -693593
+	maxSpreadsheetSerial = 2958466
+)
+
+// spreadsheetSerialTime converts a spreadsheet serial date to a time.Time. It
+// rejects serials which are not finite or outside of the years 1 to 9999.
+func spreadsheetSerialTime(serial float64) (time.Time, error) {
+	if math.IsNaN(serial) || serial < minSpreadsheetSerial || serial >= maxSpreadsheetSerial {
+		return time.Time{}, errors.Errorf("types.NullTime: spreadsheet serial %v is out of range", serial)
+	}
+	days := math.Floor(serial)
+	frac := time.Duration(math.Round((serial - days) * float64(24*time.Hour)))
+	return spreadsheetEpoch.AddDate(0, 0, int(days)).Add(frac), nil
+}
+
+func scanTimeString(s string, loc *time.Location) (time.Time, string, error) {
+	if isMySQLZeroDate(s) {
+		return time.Time{}, "", nil
+	}
+	// RFC 3339 is always accepted, even if NullTimeLayouts is misconfigured.
+	var t time.Time
+	if err := t.UnmarshalText([]byte(s)); err == nil {
+		return t, time.RFC3339, nil
+	}
+	if NullTimeDurationSinceEpoch {
+		if d, err := time.ParseDuration(s); err == nil {
+			return time.Unix(0, 0).Add(d).UTC(), "", nil
+		}
+	}
+	if NullTimeISODates {
+		if t, ok := parseISODate(s, loc); ok {
+			return t, "", nil
+		}
+	}
+	if NullTimeEpochUnit > 0 && isEpochString(s) {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, "", errors.Wrapf(ErrNullTimeEpochOverflow, "%s does not fit into int64", s)
+		}
+		t, err := epochTime(i)
+		return t, "", err
+	}
+	var layout string
+	var err error
+	if layouts := nullTimeLayouts(); len(layouts) > 0 {
+		t, layout, err = parseTimeLayouts(layouts, s, loc)
+	} else {
+		err = errors.Errorf("no layout matched %q: tried %q", s, time.RFC3339)
+	}
+	if err != nil && OnNullTimeParseError != nil {
+		OnNullTimeParseError(s)
+	}
+	return t, layout, err
+}
+
+func scanNativeTime(value interface{}) (time.Time, error) {
+	var v sql.NullTime
+	if err := (&v).Scan(value); err != nil {
+		return time.Time{}, err
+	}
+	return v.Time, nil
+}
+
+// isMySQLZeroDate reports whether s is one of the zero dates MySQL returns in
+// non-strict mode, such as "0000-00-00" or "0000-00-00 00:00:00".
+func isMySQLZeroDate(s string) bool {
+	switch s {
+	case "0000-00-00", "0000-00-00 00:00:00":
+		return true
+	}
+	return strings.HasPrefix(s, "0000-00-00 00:00:00.") && strings.Trim(s[len("0000-00-00 00:00:00."):], "0") == ""
+}
+
+// nullTimeLayouts returns the layouts NullTime.Scan tries for string values.
+func nullTimeLayouts() []string {
+	if !NullTimeHTTPDates {
+		return NullTimeLayouts
+	}
+	layouts := make([]string, 0, len(NullTimeLayouts)+2)
+	return append(append(layouts, NullTimeLayouts...), time.RFC1123, time.RFC1123Z)
+}
+
+// parseTimeLayouts parses value using the first matching layout and returns
+// it. Values without time zone information are interpreted in loc.
+func parseTimeLayouts(layouts []string, value string, loc *time.Location) (time.Time, string, error) {
+	attempts := make([]string, 0, len(layouts))
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return t, layout, nil
+		}
+		attempts = append(attempts, fmt.Sprintf("layout %q: %s", layout, err))
+	}
+	return time.Time{}, "", errors.Errorf("no layout matched %q: tried %s", value, strings.Join(attempts, "; "))
+}
+
+// clampTime applies NullTimeMin and NullTimeMax to t. Zero times are never clamped.
+func clampTime(t time.Time) (time.Time, error) {
+	if t.IsZero() {
+		return t, nil
+	}
+	if !NullTimeMin.IsZero() && t.Before(NullTimeMin) {
+		if !NullTimeClamp {
+			return t, errors.Wrapf(ErrNullTimeOutOfRange, "%s is before %s", t, NullTimeMin)
+		}
+		return NullTimeMin, nil
+	}
+	if !NullTimeMax.IsZero() && t.After(NullTimeMax) {
+		if !NullTimeClamp {
+			return t, errors.Wrapf(ErrNullTimeOutOfRange, "%s is after %s", t, NullTimeMax)
+		}
+		return NullTimeMax, nil
+	}
+	return t, nil
+}
+
+// ParseNullTimes parses each of values with layout, in UTC unless the value
+// contains time zone information. Empty strings and NullTimeNullStrings are
+// NULL. If layout is empty, each value is detected like NullTime.Scan does,
+// but the layout which matched the previous value is tried first, so that a
+// column of uniformly formatted values is parsed with a single attempt each.
+// NullTimeMin and NullTimeMax are applied. The error for the first value which
+// cannot be parsed names its index.
+func ParseNullTimes(layout string, values []string) ([]NullTime, error) {
+	out := make([]NullTime, len(values))
+	last := layout
+	for i, v := range values {
+		if v == "" || isNullTimeSentinel(v) {
+			continue
+		}
+		var t time.Time
+		var err error
+		if last != "" {
+			t, err = time.ParseInLocation(last, v, time.UTC)
+		}
+		if last == "" || (err != nil && layout == "") {
+			t, last, err = scanTimeString(v, time.UTC)
+		}
+		if err == nil {
+			t, err = clampTime(t.Round(0))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "types.ParseNullTimes: unable to parse element %d", i)
+		}
+		out[i] = NullTime(t)
+	}
+	return out, nil
+}
+
+// NullTimeFromPtr returns a NullTime which is NULL if t is nil.
+func NullTimeFromPtr(t *time.Time) NullTime {
+	if t == nil {
+		return NullTime{}
+	}
+	return NullTime(*t)
+}
+
+// Ptr returns a pointer to a copy of the underlying time, or nil if ns is NULL.
+func (ns NullTime) Ptr() *time.Time {
+	if ns.IsZero() {
+		return nil
+	}
+	t := time.Time(ns)
+	return &t
+}
+
+// Format returns ns formatted with layout as time.Time.Format does, or an empty
+// string if ns is NULL.
+func (ns NullTime) Format(layout string) string {
+	if ns.IsZero() {
+		return ""
+	}
+	return time.Time(ns).Format(layout)
+}
+
+// Compare returns -1 if ns is before other, 1 if it is after other, and 0 if
+// both are equal. NULL sorts after every non-NULL value, matching the default
+// ordering of PostgreSQL, and two NULLs compare as equal.
+func (ns NullTime) Compare(other NullTime) int {
+	switch {
+	case ns.IsZero() && other.IsZero():
+		return 0
+	case ns.IsZero():
+		return 1
+	case other.IsZero():
+		return -1
+	case time.Time(ns).Before(time.Time(other)):
+		return -1
+	case time.Time(ns).After(time.Time(other)):
+		return 1
+	}
+	return 0
+}
+
+// EqualTime reports whether ns is the same time instant as t. Like a comparison
+// with NULL in SQL, a NULL ns is neither equal to, before, nor after any t,
+// including the zero time.Time.
+func (ns NullTime) EqualTime(t time.Time) bool {
+	return !ns.IsZero() && time.Time(ns).Equal(t)
+}
+
+// BeforeTime reports whether ns is before t. It is false if ns is NULL.
+func (ns NullTime) BeforeTime(t time.Time) bool {
+	return !ns.IsZero() && time.Time(ns).Before(t)
+}
+
+// AfterTime reports whether ns is after t. It is false if ns is NULL.
+func (ns NullTime) AfterTime(t time.Time) bool {
+	return !ns.IsZero() && time.Time(ns).After(t)
+}
+
+// formatUnixSeconds formats t as exact decimal seconds since the Unix epoch.
+func formatUnixSeconds(t time.Time) string {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	sign := ""
+	if sec < 0 {
+		sign = "-"
+		if nsec > 0 {
+			sec, nsec = sec+1, int64(time.Second)-nsec
+		}
+		sec = -sec
+	}
+	s := fmt.Sprintf("%s%d", sign, sec)
+	if nsec > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", nsec), "0")
+	}
+	return s
+}
+
+// parseUnixSeconds parses decimal seconds since the Unix epoch without losing
+// precision up to nanoseconds. s must be a JSON number; exponents are parsed as
+// float64.
+func parseUnixSeconds(s string) (time.Time, error) {
+	if !isJSONNumber([]byte(s)) {
+		return time.Time{}, errors.Errorf("types.NullTime: unable to parse %q as Unix seconds", s)
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f >= math.MaxInt64 || f < math.MinInt64 {
+			return time.Time{}, errors.Errorf("types.NullTime: unable to parse %q as Unix seconds", s)
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	whole, frac := strings.TrimPrefix(s, "-"), ""
+	if i := strings.IndexByte(whole, '.'); i >= 0 {
+		whole, frac = whole[:i], whole[i+1:]
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+
+	sec, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Errorf("types.NullTime: unable to parse %q as Unix seconds", s)
+	}
+	var nsec int64
+	if frac != "" {
+		if nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64); err != nil {
+			return time.Time{}, errors.Errorf("types.NullTime: unable to parse %q as Unix seconds", s)
+		}
+	}
+	if negative {
+		sec, nsec = -sec, -nsec
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+type locationContextKey struct{}
+
+// WithLocation returns a copy of ctx carrying loc for use by NullTime.ScanContext.
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationContextKey{}, loc)
+}
+
+// LocationFromContext returns the location attached to ctx using WithLocation,
+// or time.UTC if there is none.
+func LocationFromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationContextKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
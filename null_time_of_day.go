@@ -0,0 +1,105 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const timeOfDayLayout = "15:04:05"
+
+// NullTimeOfDay represents a NULLable time of day without a date, such as a
+// TIME column. It is encoded as "15:04:05" in JSON and SQL. It has a resolution
+// of one second: Scan and UnmarshalJSON truncate fractional seconds, so that
+// "10:00:00.5" is stored as 10:00:00.
+type NullTimeOfDay struct {
+	Hour, Minute, Second int
+	Valid                bool
+}
+
+func timeOfDayFromTime(t time.Time) NullTimeOfDay {
+	return NullTimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Valid: true}
+}
+
+// parseTimeOfDay parses the clock portion of s, which is either a time of day
+// or a full timestamp. Fractional seconds are discarded.
+func parseTimeOfDay(s string) (NullTimeOfDay, error) {
+	for _, layout := range []string{timeOfDayLayout + ".999999999", time.RFC3339Nano, "2006-01-02 15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return timeOfDayFromTime(t), nil
+		}
+	}
+	return NullTimeOfDay{}, errors.Errorf("unable to parse %q as a time of day", s)
+}
+
+// Scan implements the Scanner interface.
+func (t *NullTimeOfDay) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
+	}
+	switch v := value.(type) {
+	case nil:
+		*t = NullTimeOfDay{}
+	case time.Time:
+		*t = timeOfDayFromTime(v)
+	case string, []byte:
+		parsed, err := parseTimeOfDay(fmt.Sprintf("%s", v))
+		if err != nil {
+			return scanError("NullTimeOfDay", value, err)
+		}
+		*t = parsed
+	default:
+		return scanError("NullTimeOfDay", value, errors.New("unsupported type"))
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (t NullTimeOfDay) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.String(), nil
+}
+
+// String implements the Stringer interface. It returns an empty string if t is NULL.
+func (t NullTimeOfDay) String() string {
+	if !t.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// MarshalJSON encodes t as a "15:04:05" JSON string, or null if t is NULL.
+func (t NullTimeOfDay) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte(jsonNull), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON sets *t to the time of day in the JSON string in data,
+// truncating fractional seconds. JSON null sets *t to NULL.
+func (t *NullTimeOfDay) UnmarshalJSON(data []byte) error {
+	if t == nil {
+		return errors.New("types.NullTimeOfDay: UnmarshalJSON on nil pointer")
+	}
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.WithStack(err)
+	}
+	if s == nil {
+		*t = NullTimeOfDay{}
+		return nil
+	}
+	parsed, err := time.Parse(timeOfDayLayout+".999999999", *s)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	*t = timeOfDayFromTime(parsed)
+	return nil
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullTimeOfDay(t *testing.T) {
+	expected := NullTimeOfDay{Hour: 15, Minute: 4, Second: 5, Valid: true}
+
+	for _, in := range []interface{}{
+		"15:04:05",
+		[]byte("15:04:05.123456"),
+		"2006-01-02T15:04:05Z",
+		"2006-01-02 15:04:05",
+		time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+	} {
+		t.Run(fmt.Sprintf("in=%v", in), func(t *testing.T) {
+			var actual NullTimeOfDay
+			require.NoError(t, actual.Scan(in))
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	v, err := expected.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "15:04:05", v)
+
+	out, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assert.Equal(t, `"15:04:05"`, string(out))
+
+	var actual NullTimeOfDay
+	require.NoError(t, json.Unmarshal(out, &actual))
+	assert.Equal(t, expected, actual)
+
+	// Fractional seconds are truncated, so that scanning and JSON agree.
+	require.NoError(t, actual.Scan("15:04:05.5"))
+	out, err = json.Marshal(actual)
+	require.NoError(t, err)
+	assert.Equal(t, `"15:04:05"`, string(out))
+	require.NoError(t, json.Unmarshal([]byte(`"15:04:05.5"`), &actual))
+	assert.Equal(t, expected, actual)
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &actual))
+	assert.False(t, actual.Valid)
+	require.NoError(t, actual.Scan(nil))
+	assert.False(t, actual.Valid)
+
+	v, err = actual.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	out, err = json.Marshal(actual)
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(out))
+
+	require.Error(t, actual.Scan("25:00:00"))
+	require.Error(t, actual.Scan(int64(1)))
+	require.Error(t, json.Unmarshal([]byte(`"noon"`), &actual))
+}
@@ -0,0 +1,844 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullTimeClamp(t *testing.T) {
+	far := time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	max := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var nt NullTime
+	require.NoError(t, nt.Scan(far))
+	assert.True(t, far.Equal(time.Time(nt)))
+
+	NullTimeMax = max
+	defer func() { NullTimeMax, NullTimeClamp = time.Time{}, false }()
+
+	err := nt.Scan(far)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNullTimeOutOfRange))
+
+	NullTimeClamp = true
+	require.NoError(t, nt.Scan(far))
+	assert.True(t, max.Equal(time.Time(nt)))
+
+	require.NoError(t, nt.Scan(nil))
+	assert.True(t, time.Time(nt).IsZero())
+}
+
+func TestNullTimeEqual(t *testing.T) {
+	now := time.Now()
+	stripped := now.Round(0)
+	require.NotEqual(t, now, stripped)
+
+	assert.True(t, NullTime(now).Equal(NullTime(stripped)))
+	assert.True(t, NullTime(now).Equal(NullTime(now.In(time.FixedZone("X", 3600)))))
+	assert.False(t, NullTime(now).Equal(NullTime(now.Add(time.Nanosecond))))
+	assert.False(t, NullTime(now).Equal(NullTime{}))
+	assert.True(t, NullTime{}.Equal(NullTime{}))
+
+	assert.True(t, NullTime{}.IsZero())
+	assert.False(t, NullTime(now).IsZero())
+}
+
+func TestNullTimeScanStripsMonotonic(t *testing.T) {
+	now := time.Now()
+	require.NotEqual(t, now, now.Round(0))
+
+	var nt NullTime
+	require.NoError(t, nt.Scan(now))
+	assert.Equal(t, NullTime(now.Round(0)), nt)
+
+	out, err := json.Marshal(nt)
+	require.NoError(t, err)
+
+	var actual NullTime
+	require.NoError(t, json.Unmarshal(out, &actual))
+	assert.Equal(t, time.Time(nt).UnixNano(), time.Time(actual).UnixNano())
+	assert.Equal(t, nt, NullTime(time.Time(actual).In(now.Location())))
+}
+
+func TestNullTimeNullStrings(t *testing.T) {
+	var nt NullTime
+	require.NoError(t, nt.UnmarshalText([]byte("")))
+	assert.True(t, nt.IsZero())
+	require.NoError(t, json.Unmarshal([]byte(`null`), &nt))
+	assert.True(t, nt.IsZero())
+
+	require.Error(t, nt.UnmarshalText([]byte("N/A")))
+	require.Error(t, json.Unmarshal([]byte(`"N/A"`), &nt))
+
+	NullTimeNullStrings = []string{"N/A"}
+	defer func() { NullTimeNullStrings = nil }()
+
+	nt = NullTime(time.Now())
+	require.NoError(t, nt.UnmarshalText([]byte("N/A")))
+	assert.True(t, nt.IsZero())
+
+	nt = NullTime(time.Now())
+	require.NoError(t, json.Unmarshal([]byte(`"N/A"`), &nt))
+	assert.True(t, nt.IsZero())
+
+	require.NoError(t, nt.UnmarshalText([]byte("2006-01-02T15:04:05Z")))
+	assert.True(t, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Equal(time.Time(nt)))
+}
+
+func TestNullTimePtr(t *testing.T) {
+	assert.Nil(t, NullTime{}.Ptr())
+	assert.True(t, NullTimeFromPtr(nil).IsZero())
+
+	now := time.Now()
+	assert.Equal(t, now, *NullTime(now).Ptr())
+	assert.Equal(t, NullTime(now), NullTimeFromPtr(&now))
+}
+
+func TestNullTimeLayouts(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan("2006-01-02 15:04:05"))
+
+	NullTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "01/02/2006"}
+	defer func() { NullTimeLayouts = nil }()
+
+	for _, tc := range []struct {
+		in     interface{}
+		expect time.Time
+	}{
+		{in: "2006-01-02T15:04:05+01:00", expect: time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC)},
+		{in: []byte("2006-01-02 15:04:05"), expect: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{in: "01/02/2006", expect: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(fmt.Sprintf("in=%s", tc.in), func(t *testing.T) {
+			var nt NullTime
+			require.NoError(t, nt.Scan(tc.in))
+			assert.True(t, tc.expect.Equal(time.Time(nt)), "%s", time.Time(nt))
+		})
+	}
+
+	err := nt.Scan("yesterday")
+	require.Error(t, err)
+	for _, layout := range NullTimeLayouts {
+		assert.Contains(t, err.Error(), layout)
+	}
+}
+
+func TestNullTimeCompare(t *testing.T) {
+	a := NullTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NullTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 0, NullTime{}.Compare(NullTime{}))
+	assert.Equal(t, 1, NullTime{}.Compare(a))
+	assert.Equal(t, -1, a.Compare(NullTime{}))
+	assert.Equal(t, -1, a.Compare(b))
+	assert.Equal(t, 1, b.Compare(a))
+	assert.Equal(t, 0, a.Compare(NullTime(time.Time(a).In(time.FixedZone("X", 3600)))))
+
+	times := []NullTime{b, {}, a}
+	sort.Slice(times, func(i, j int) bool { return times[i].Compare(times[j]) < 0 })
+	assert.Equal(t, []NullTime{a, b, {}}, times)
+}
+
+func TestNullTimeMarshalJSONUTC(t *testing.T) {
+	for _, loc := range []*time.Location{time.UTC, time.FixedZone("UTC", 0), time.FixedZone("", 0)} {
+		out, err := json.Marshal(NullTime(time.Date(2021, 1, 1, 12, 0, 0, 0, loc)))
+		require.NoError(t, err)
+		assert.Equal(t, `"2021-01-01T12:00:00Z"`, string(out))
+	}
+
+	out, err := json.Marshal(NullTime(time.Date(2021, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))))
+	require.NoError(t, err)
+	assert.Equal(t, `"2021-01-01T12:00:00+01:00"`, string(out))
+}
+
+func TestNullTimeScanMySQLZeroDate(t *testing.T) {
+	for _, in := range []interface{}{"0000-00-00", []byte("0000-00-00 00:00:00"), "0000-00-00 00:00:00.000000"} {
+		t.Run(fmt.Sprintf("in=%s", in), func(t *testing.T) {
+			nt := NullTime(time.Now())
+			require.NoError(t, nt.Scan(in))
+			assert.True(t, nt.IsZero())
+		})
+	}
+
+	var nt NullTime
+	require.Error(t, nt.Scan("0000-00-00 00:00:01"))
+}
+
+func TestNullTimeSpreadsheetSerial(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan(44197.5))
+
+	NullTimeSpreadsheetSerial = true
+	defer func() { NullTimeSpreadsheetSerial = false }()
+
+	require.NoError(t, nt.Scan(44197.5))
+	assert.Equal(t, time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC), time.Time(nt))
+
+	require.NoError(t, nt.Scan(float64(1)))
+	assert.Equal(t, time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan(float64(2958465.5)))
+	assert.Equal(t, time.Date(9999, 12, 31, 12, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan(float64(-693592)))
+	assert.Equal(t, time.Date(1, 1, 2, 0, 0, 0, 0, time.UTC), time.Time(nt))
+
+	for _, in := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e300, -1e300, 2958466, -693594} {
+		err := nt.Scan(in)
+		require.Error(t, err, "%v", in)
+		assert.Contains(t, err.Error(), "types.NullTime: spreadsheet serial")
+		assert.Contains(t, err.Error(), "is out of range")
+	}
+}
+
+func TestNullTimeNullAsEpoch(t *testing.T) {
+	NullTimeNullAsEpoch = true
+	local := time.Local
+	time.Local = time.FixedZone("UTC+1", 60*60)
+	defer func() { NullTimeNullAsEpoch, time.Local = false, local }()
+
+	out, err := json.Marshal(NullTime{})
+	require.NoError(t, err)
+	assert.Equal(t, `"1970-01-01T00:00:00Z"`, string(out))
+
+	nt := NullTime(time.Now())
+	require.NoError(t, json.Unmarshal(out, &nt))
+	assert.True(t, nt.IsZero())
+
+	require.NoError(t, json.Unmarshal([]byte(`"1970-01-01T01:00:00+01:00"`), &nt))
+	assert.True(t, nt.IsZero())
+
+	require.NoError(t, json.Unmarshal([]byte(`"1970-01-01T00:00:01Z"`), &nt))
+	assert.False(t, nt.IsZero())
+}
+
+func TestNullTimeHTTPDates(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan("Mon, 02 Jan 2006 15:04:05 GMT"))
+
+	NullTimeHTTPDates = true
+	defer func() { NullTimeHTTPDates = false }()
+
+	require.NoError(t, nt.Scan("Mon, 02 Jan 2006 15:04:05 GMT"))
+	assert.True(t, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Equal(time.Time(nt)))
+
+	require.NoError(t, nt.Scan([]byte("Mon, 02 Jan 2006 15:04:05 -0700")))
+	assert.True(t, time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC).Equal(time.Time(nt)))
+}
+
+func TestNullTimeMapKey(t *testing.T) {
+	in := map[NullTime]int{
+		{}: 1,
+		NullTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)): 2,
+	}
+
+	out, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"":1,"2021-01-01T00:00:00Z":2}`, string(out))
+
+	var actual map[NullTime]int
+	require.NoError(t, json.Unmarshal(out, &actual))
+	assert.Equal(t, in, actual)
+}
+
+func TestNullTimeEpoch(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan(int64(1609459200)))
+
+	NullTimeEpochUnit = time.Second
+	defer func() { NullTimeEpochUnit = 0 }()
+
+	require.NoError(t, nt.Scan(int64(1609459200)))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Time(nt))
+
+	// One second past the maximum of signed 32-bit Unix time.
+	require.NoError(t, nt.Scan(int64(math.MaxInt32)+1))
+	assert.Equal(t, time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan(uint32(math.MaxInt32)+1))
+	assert.Equal(t, time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC), time.Time(nt))
+
+	for _, in := range []interface{}{int(1609459200), int16(0), int8(0), uint(1609459200), uint16(0), uint8(0)} {
+		require.NoError(t, nt.Scan(in), "%T", in)
+	}
+	assert.Equal(t, time.Unix(0, 0).UTC(), time.Time(nt))
+	require.NoError(t, nt.Scan(uint(1609459200)))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan(int16(-1)))
+	assert.Equal(t, time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), time.Time(nt))
+
+	for _, in := range []interface{}{int64(math.MaxInt64 / 1000), int64(math.MinInt64 / 1000), uint64(math.MaxUint64)} {
+		err := nt.Scan(in)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrNullTimeEpochOverflow), "%+v", err)
+	}
+
+	NullTimeEpochUnit = time.Millisecond
+	require.NoError(t, nt.Scan(int64(1609459200123)))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 123000000, time.UTC), time.Time(nt))
+}
+
+func TestNullTimeEpochString(t *testing.T) {
+	NullTimeLayouts = []string{"20060102"}
+	defer func() { NullTimeLayouts = nil }()
+
+	var nt NullTime
+	require.NoError(t, nt.Scan("20210101"))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Time(nt))
+
+	NullTimeEpochUnit = time.Second
+	defer func() { NullTimeEpochUnit = 0 }()
+
+	require.NoError(t, nt.Scan("1609459200"))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan([]byte("-86400")))
+	assert.Equal(t, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan("20210101"))
+	assert.Equal(t, time.Unix(20210101, 0).UTC(), time.Time(nt))
+
+	for _, in := range []string{"9223372036854775807", "99999999999999999999"} {
+		err := nt.Scan(in)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrNullTimeEpochOverflow), "%+v", err)
+	}
+	for _, in := range []string{"-", "+1609459200", "1609459200.5", " 1609459200"} {
+		assert.Error(t, nt.Scan(in), "%s", in)
+	}
+
+	NullTimeISODates = true
+	defer func() { NullTimeISODates = false }()
+	require.NoError(t, nt.Scan("2006002"))
+	assert.Equal(t, time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	require.NoError(t, nt.Scan("2006400"))
+	assert.Equal(t, time.Unix(2006400, 0).UTC(), time.Time(nt))
+	require.NoError(t, nt.Scan("1609459200"))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Time(nt))
+	NullTimeISODates = false
+
+	NullTimeEpochUnit = time.Millisecond
+	require.NoError(t, nt.Scan("1609459200123"))
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 123000000, time.UTC), time.Time(nt))
+}
+
+func TestNullTimeScanRFC3339(t *testing.T) {
+	NullTimeLayouts = []string{"not a layout"}
+	defer func() { NullTimeLayouts = nil }()
+
+	var nt NullTime
+	require.NoError(t, nt.Scan([]byte("2006-01-02T15:04:05.123Z")))
+	assert.Equal(t, time.Date(2006, 1, 2, 15, 4, 5, 123000000, time.UTC), time.Time(nt))
+
+	NullTimeLayouts = nil
+	require.NoError(t, nt.Scan("2006-01-02T15:04:05+01:00"))
+	assert.True(t, time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC).Equal(time.Time(nt)))
+}
+
+func TestNullTimeUnixSeconds(t *testing.T) {
+	NullTimeUnixSeconds = true
+	defer func() { NullTimeUnixSeconds = false }()
+
+	for _, tc := range []struct {
+		in     time.Time
+		expect string
+	}{
+		{in: time.Date(2021, 1, 1, 0, 0, 0, 123456000, time.UTC), expect: `1609459200.123456`},
+		{in: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), expect: `1609459200`},
+		{in: time.Date(2021, 1, 1, 0, 0, 0, 1, time.FixedZone("X", 3600)), expect: `1609455600.000000001`},
+		{in: time.Unix(-1, 500000000), expect: `-0.5`},
+		{in: time.Unix(-2, 250000000), expect: `-1.75`},
+	} {
+		t.Run("expect="+tc.expect, func(t *testing.T) {
+			out, err := json.Marshal(NullTime(tc.in))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, string(out))
+
+			var actual NullTime
+			require.NoError(t, json.Unmarshal(out, &actual))
+			assert.True(t, tc.in.Equal(time.Time(actual)), "%s", time.Time(actual))
+		})
+	}
+
+	out, err := json.Marshal(NullTime{})
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(out))
+
+	nt := NullTime(time.Now())
+	require.NoError(t, json.Unmarshal([]byte(`null`), &nt))
+	assert.True(t, nt.IsZero())
+
+	require.NoError(t, json.Unmarshal([]byte(`1.6094592e9`), &nt))
+	assert.Equal(t, int64(1609459200), time.Time(nt).Unix())
+
+	for _, in := range []string{`1.-5`, `1.+5`, `-1.-5`, `--1`, `-`, `1.`, `1e`, `1e400`, `-1e300`, `1.5e-`} {
+		err := nt.UnmarshalJSON([]byte(in))
+		require.Error(t, err, "%s", in)
+		assert.Equal(t, fmt.Sprintf("types.NullTime: unable to parse %q as Unix seconds", in), err.Error())
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`"2021-01-01T00:00:00Z"`), &nt))
+	assert.Equal(t, int64(1609459200), time.Time(nt).Unix())
+}
+
+func TestNullTimeScanContext(t *testing.T) {
+	NullTimeLayouts = []string{"2006-01-02 15:04:05"}
+	defer func() { NullTimeLayouts = nil }()
+
+	berlin := time.FixedZone("Europe/Berlin", 3600)
+	tokyo := time.FixedZone("Asia/Tokyo", 9*3600)
+
+	var a, b, c NullTime
+	require.NoError(t, a.ScanContext(WithLocation(context.Background(), berlin), "2021-01-01 12:00:00"))
+	require.NoError(t, b.ScanContext(WithLocation(context.Background(), tokyo), "2021-01-01 12:00:00"))
+	require.NoError(t, c.ScanContext(context.Background(), "2021-01-01 12:00:00"))
+
+	_, offset := time.Time(a).Zone()
+	assert.Equal(t, 3600, offset)
+	_, offset = time.Time(b).Zone()
+	assert.Equal(t, 9*3600, offset)
+	assert.Equal(t, time.UTC, time.Time(c).Location())
+	assert.Equal(t, 8*time.Hour, time.Time(a).Sub(time.Time(b)))
+
+	// Values with an explicit offset are not affected.
+	require.NoError(t, a.ScanContext(WithLocation(context.Background(), berlin), "2021-01-01T12:00:00Z"))
+	assert.True(t, time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC).Equal(time.Time(a)))
+
+	assert.Equal(t, time.UTC, LocationFromContext(context.Background()))
+}
+
+func TestNullTimeScanMatrix(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+	for k, tc := range []struct {
+		in     interface{}
+		expect time.Time
+		err    bool
+	}{
+		{in: nil},
+		{in: (*time.Time)(nil)},
+		{in: ([]byte)(nil)},
+		{in: ts, expect: ts},
+		{in: ts.Format(time.RFC3339Nano), expect: ts},
+		{in: []byte(ts.Format(time.RFC3339Nano)), expect: ts},
+		{in: "0000-00-00 00:00:00"},
+		{in: "not a time", err: true},
+		{in: []byte("not a time"), err: true},
+		{in: "", err: true},
+		{in: int64(1), err: true},
+		{in: float64(1), err: true},
+		{in: true, err: true},
+		{in: struct{}{}, err: true},
+	} {
+		t.Run(fmt.Sprintf("case=%d/type=%T", k, tc.in), func(t *testing.T) {
+			nt := NullTime(time.Now())
+			err := nt.Scan(tc.in)
+			if tc.err {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), fmt.Sprintf("types: unable to scan into NullTime from %T: ", tc.in))
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expect.Equal(time.Time(nt)), "%s", time.Time(nt))
+		})
+	}
+}
+
+func TestNullTimeScanErrorsWrapSentinels(t *testing.T) {
+	NullTimeMax = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	NullTimeEpochUnit = time.Second
+	defer func() { NullTimeMax, NullTimeEpochUnit = time.Time{}, 0 }()
+
+	var nt NullTime
+	err := nt.Scan(time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNullTimeOutOfRange))
+	assert.Contains(t, err.Error(), "types: ")
+
+	err = nt.Scan(int64(math.MaxInt64))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNullTimeEpochOverflow))
+	assert.Contains(t, err.Error(), "types: ")
+}
+
+func TestNullTimeMarshalWholeSeconds(t *testing.T) {
+	nt := NullTime(time.Date(2006, 1, 2, 15, 4, 5, 999999999, time.UTC))
+
+	out, err := json.Marshal(nt)
+	require.NoError(t, err)
+	assert.Equal(t, `"2006-01-02T15:04:05.999999999Z"`, string(out))
+
+	NullTimeMarshalWholeSeconds = true
+	defer func() { NullTimeMarshalWholeSeconds = false }()
+
+	out, err = json.Marshal(nt)
+	require.NoError(t, err)
+	assert.Equal(t, `"2006-01-02T15:04:05Z"`, string(out))
+
+	NullTimeUnixSeconds = true
+	defer func() { NullTimeUnixSeconds = false }()
+
+	out, err = json.Marshal(nt)
+	require.NoError(t, err)
+	assert.Equal(t, `1136214245`, string(out))
+}
+
+func TestNullTimeScanLayout(t *testing.T) {
+	NullTimeLayouts = []string{"2006-01-02", "02.01.2006 15:04"}
+	defer func() { NullTimeLayouts = nil }()
+
+	for _, tc := range []struct {
+		in     interface{}
+		layout string
+	}{
+		{in: "2021-03-04", layout: "2006-01-02"},
+		{in: []byte("04.03.2021 05:06"), layout: "02.01.2006 15:04"},
+		{in: "2021-03-04T05:06:07Z", layout: time.RFC3339},
+		{in: time.Now(), layout: ""},
+		{in: nil, layout: ""},
+	} {
+		var nt NullTime
+		layout, err := nt.ScanLayout(tc.in)
+		require.NoError(t, err)
+		assert.Equal(t, tc.layout, layout, "%v", tc.in)
+	}
+
+	var nt NullTime
+	layout, err := nt.ScanLayout("garbage")
+	require.Error(t, err)
+	assert.Empty(t, layout)
+}
+
+func TestParseNullTimes(t *testing.T) {
+	first := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC)
+
+	actual, err := ParseNullTimes("2006-01-02", []string{"2021-03-04", "", "2022-05-06"})
+	require.NoError(t, err)
+	require.Len(t, actual, 3)
+	assert.True(t, first.Equal(time.Time(actual[0])))
+	assert.True(t, actual[1].IsZero())
+	assert.True(t, second.Equal(time.Time(actual[2])))
+
+	_, err = ParseNullTimes("2006-01-02", []string{"2021-03-04", "04.03.2021"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "element 1")
+
+	NullTimeLayouts = []string{"02.01.2006", "2006-01-02"}
+	defer func() { NullTimeLayouts = nil }()
+
+	actual, err = ParseNullTimes("", []string{"2021-03-04", "06.05.2022", "2021-03-04T00:00:00Z"})
+	require.NoError(t, err)
+	assert.True(t, first.Equal(time.Time(actual[0])))
+	assert.True(t, second.Equal(time.Time(actual[1])))
+	assert.True(t, first.Equal(time.Time(actual[2])))
+
+	_, err = ParseNullTimes("", []string{"2021-03-04", "garbage"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1: no layout matched "garbage": tried layout "02.01.2006": `)
+
+	NullTimeLayouts = nil
+	_, err = ParseNullTimes("", []string{"2021-01-01T00:00:00Z", "2021-01-01 00:00:00"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `types.ParseNullTimes: unable to parse element 1: no layout matched "2021-01-01 00:00:00": tried "2006-01-02T15:04:05Z07:00"`)
+	assert.NotContains(t, err.Error(), "unsupported Scan")
+
+	var nt NullTime
+	err = nt.Scan("garbage")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no layout matched "garbage"`)
+	assert.NotContains(t, err.Error(), "unsupported Scan")
+}
+
+func TestNullTimeAssumeUTC(t *testing.T) {
+	local := time.Date(2021, 3, 4, 12, 0, 0, 5, time.FixedZone("CEST", 2*3600))
+
+	var nt NullTime
+	require.NoError(t, nt.Scan(local))
+	assert.True(t, local.Equal(time.Time(nt)))
+
+	NullTimeAssumeUTC = true
+	defer func() { NullTimeAssumeUTC = false }()
+
+	require.NoError(t, nt.Scan(local))
+	assert.Equal(t, time.Date(2021, 3, 4, 12, 0, 0, 5, time.UTC), time.Time(nt))
+	assert.Equal(t, time.UTC, time.Time(nt).Location())
+
+	require.NoError(t, nt.Scan(time.Time{}))
+	assert.True(t, nt.IsZero())
+	require.NoError(t, nt.Scan(nil))
+	assert.True(t, nt.IsZero())
+}
+
+type fakeOracleTimestamp struct {
+	unix int64
+}
+
+// restoreNullTimeConverters resets the converter registry when t finishes.
+func restoreNullTimeConverters(t *testing.T) {
+	nullTimeConvertersMu.RLock()
+	converters := nullTimeConverters
+	nullTimeConvertersMu.RUnlock()
+	t.Cleanup(func() {
+		nullTimeConvertersMu.Lock()
+		defer nullTimeConvertersMu.Unlock()
+		nullTimeConverters = converters
+	})
+}
+
+func TestRegisterNullTimeConverter(t *testing.T) {
+	restoreNullTimeConverters(t)
+
+	var nt NullTime
+	require.Error(t, nt.Scan(fakeOracleTimestamp{unix: 1}))
+
+	RegisterNullTimeConverter(func(value interface{}) (time.Time, bool, error) {
+		v, ok := value.(fakeOracleTimestamp)
+		if !ok {
+			return time.Time{}, false, nil
+		}
+		if v.unix < 0 {
+			return time.Time{}, true, errors.New("negative timestamp")
+		}
+		return time.Unix(v.unix, 0).UTC(), true, nil
+	})
+
+	require.NoError(t, nt.Scan(fakeOracleTimestamp{unix: 1609459200}))
+	assert.True(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Equal(time.Time(nt)))
+
+	err := nt.Scan(fakeOracleTimestamp{unix: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "negative timestamp")
+
+	require.Error(t, nt.Scan(struct{}{}))
+}
+
+func TestNullTimeFormat(t *testing.T) {
+	assert.Equal(t, "", NullTime{}.Format(time.RFC3339))
+	nt := NullTime(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	assert.Equal(t, "2021-03-04T05:06:07Z", nt.Format(time.RFC3339))
+	assert.Equal(t, "04.03.2021", nt.Format("02.01.2006"))
+}
+
+func TestOnNullTimeParseError(t *testing.T) {
+	var failed []string
+	OnNullTimeParseError = func(raw string) { failed = append(failed, raw) }
+	defer func() { OnNullTimeParseError = nil }()
+
+	var nt NullTime
+	require.NoError(t, nt.Scan("2021-03-04T05:06:07Z"))
+	require.NoError(t, nt.Scan(time.Now()))
+	require.Error(t, nt.Scan("garbage"))
+
+	NullTimeLayouts = []string{"2006-01-02"}
+	defer func() { NullTimeLayouts = nil }()
+	require.NoError(t, nt.Scan("2021-03-04"))
+	require.Error(t, nt.Scan([]byte("04.03.2021")))
+	require.Error(t, nt.Scan(true))
+
+	assert.Equal(t, []string{"garbage", "04.03.2021"}, failed)
+}
+
+func TestNullTimeDurationSinceEpoch(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan("1609459200s"))
+
+	NullTimeDurationSinceEpoch = true
+	defer func() { NullTimeDurationSinceEpoch = false }()
+
+	expected := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, in := range []interface{}{"1609459200s", []byte("447072h"), "1609459199s1000ms"} {
+		require.NoError(t, nt.Scan(in))
+		assert.Equal(t, expected, time.Time(nt), "%s", in)
+	}
+	require.NoError(t, nt.Scan("2021-01-01T00:00:00Z"))
+	assert.True(t, expected.Equal(time.Time(nt)))
+	require.Error(t, nt.Scan("1609459200"))
+}
+
+func TestNullTimeNullJSONTokens(t *testing.T) {
+	tokens := []string{`false`, `0`, `""`}
+	for _, token := range tokens[:2] {
+		var nt NullTime
+		err := json.Unmarshal([]byte(token), &nt)
+		require.Error(t, err, "%s", token)
+		assert.Contains(t, err.Error(), "types.NullTime: unable to decode "+token)
+	}
+
+	NullTimeNullJSONTokens = tokens
+	defer func() { NullTimeNullJSONTokens = nil }()
+
+	for _, token := range tokens {
+		nt := NullTime(time.Now())
+		require.NoError(t, json.Unmarshal([]byte(token), &nt), "%s", token)
+		assert.True(t, nt.IsZero(), "%s", token)
+
+		nt = NullTime(time.Now())
+		require.NoError(t, nt.UnmarshalJSON([]byte(" "+token+" ")), "%s", token)
+		assert.True(t, nt.IsZero(), "%s", token)
+	}
+
+	var nt NullTime
+	for _, token := range []string{`true`, `0.0`, `1`, `"x"`} {
+		err := json.Unmarshal([]byte(token), &nt)
+		require.Error(t, err, "%s", token)
+		assert.Contains(t, err.Error(), "unable to decode "+token+" as a time")
+	}
+	require.NoError(t, json.Unmarshal([]byte(`null`), &nt))
+	require.NoError(t, json.Unmarshal([]byte(`"2021-01-01T00:00:00Z"`), &nt))
+	assert.False(t, nt.IsZero())
+}
+
+func TestNullTimeFractionDigits(t *testing.T) {
+	nt := NullTime(time.Date(2006, 1, 2, 15, 4, 5, 123400000, time.UTC))
+	defer func() { NullTimeFractionDigits = -1 }()
+
+	for _, tc := range []struct {
+		digits int
+		expect string
+	}{
+		{digits: -1, expect: `"2006-01-02T15:04:05.1234Z"`},
+		{digits: 0, expect: `"2006-01-02T15:04:05Z"`},
+		{digits: 3, expect: `"2006-01-02T15:04:05.123Z"`},
+		{digits: 6, expect: `"2006-01-02T15:04:05.123400Z"`},
+	} {
+		NullTimeFractionDigits = tc.digits
+		out, err := json.Marshal(nt)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expect, string(out), "%d", tc.digits)
+
+		out, err = json.Marshal(NullTime{})
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(out))
+	}
+
+	NullTimeFractionDigits = 3
+	out, err := json.Marshal(NullTime(time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("", 3600))))
+	require.NoError(t, err)
+	assert.Equal(t, `"2006-01-02T15:04:05.000+01:00"`, string(out))
+}
+
+func TestNullTimeCompareTime(t *testing.T) {
+	now := time.Now()
+	nt := NullTime(now)
+
+	assert.True(t, nt.EqualTime(now.In(time.FixedZone("X", 3600))))
+	assert.False(t, nt.BeforeTime(now))
+	assert.False(t, nt.AfterTime(now))
+
+	assert.True(t, nt.BeforeTime(now.Add(time.Second)))
+	assert.False(t, nt.AfterTime(now.Add(time.Second)))
+	assert.True(t, nt.AfterTime(now.Add(-time.Second)))
+	assert.False(t, nt.EqualTime(now.Add(time.Nanosecond)))
+
+	for _, other := range []time.Time{now, {}, time.Unix(0, 0)} {
+		assert.False(t, NullTime{}.EqualTime(other))
+		assert.False(t, NullTime{}.BeforeTime(other))
+		assert.False(t, NullTime{}.AfterTime(other))
+	}
+}
+
+type timeStringer struct {
+	s string
+}
+
+func (s timeStringer) String() string {
+	return s.s
+}
+
+func TestNullTimeScanStringer(t *testing.T) {
+	var nt NullTime
+	require.NoError(t, nt.Scan(timeStringer{s: "2021-03-04T05:06:07.5+01:00"}))
+	assert.True(t, time.Date(2021, 3, 4, 4, 6, 7, 500000000, time.UTC).Equal(time.Time(nt)))
+
+	require.NoError(t, nt.Scan(&timeStringer{s: "0000-00-00"}))
+	assert.True(t, nt.IsZero())
+
+	err := nt.Scan(timeStringer{s: "garbage"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "types: unable to scan into NullTime from types.timeStringer: ")
+}
+
+func TestNullTimeNullAsEmptyString(t *testing.T) {
+	type doc struct {
+		DeletedAt NullTime `json:"deleted_at"`
+	}
+
+	var actual doc
+	require.NoError(t, json.Unmarshal([]byte(`{"deleted_at":""}`), &actual))
+	assert.True(t, actual.DeletedAt.IsZero())
+
+	NullTimeNullAsEmptyString = true
+	defer func() { NullTimeNullAsEmptyString = false }()
+
+	for _, in := range []doc{{}, {DeletedAt: NullTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))}} {
+		out, err := json.Marshal(in)
+		require.NoError(t, err)
+		if in.DeletedAt.IsZero() {
+			assert.Equal(t, `{"deleted_at":""}`, string(out))
+		}
+		actual = doc{DeletedAt: NullTime(time.Now())}
+		require.NoError(t, json.Unmarshal(out, &actual))
+		assert.True(t, in.DeletedAt.Equal(actual.DeletedAt))
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"deleted_at":null}`), &actual))
+	assert.True(t, actual.DeletedAt.IsZero())
+}
+
+func TestTimeValidTupleConverter(t *testing.T) {
+	restoreNullTimeConverters(t)
+	RegisterNullTimeConverter(TimeValidTupleConverter)
+
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	var nt NullTime
+	require.NoError(t, nt.Scan([2]interface{}{ts, true}))
+	assert.True(t, ts.Equal(time.Time(nt)))
+
+	require.NoError(t, nt.Scan([2]interface{}{ts, false}))
+	assert.True(t, nt.IsZero())
+	require.NoError(t, nt.Scan([2]interface{}{nil, false}))
+	assert.True(t, nt.IsZero())
+
+	for _, in := range []interface{}{[2]interface{}{ts, "yes"}, [2]interface{}{"2021", true}} {
+		err := nt.Scan(in)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a time.Time and bool")
+	}
+}
+
+func TestNullTimeISODates(t *testing.T) {
+	var nt NullTime
+	require.Error(t, nt.Scan("2006-002"))
+
+	NullTimeISODates = true
+	defer func() { NullTimeISODates = false }()
+
+	for _, tc := range []struct {
+		in     string
+		expect time.Time
+	}{
+		{in: "2006-002", expect: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{in: "2006002", expect: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{in: "2004-366", expect: time.Date(2004, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{in: "2006-W01-2", expect: time.Date(2006, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{in: "2006W012", expect: time.Date(2006, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{in: "2006-W01", expect: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{in: "2009-W01-1", expect: time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{in: "2009-W53-7", expect: time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC)},
+	} {
+		require.NoError(t, nt.Scan(tc.in), "%s", tc.in)
+		assert.Equal(t, tc.expect, time.Time(nt), "%s", tc.in)
+	}
+
+	for _, in := range []string{"2006-000", "2006-366", "2006-W00-1", "2006-W53-1", "2006-W01-8", "2006-W01-0", "2006-W1-1"} {
+		require.Error(t, nt.Scan(in), "%s", in)
+	}
+
+	ctx := WithLocation(context.Background(), time.FixedZone("X", 3600))
+	require.NoError(t, nt.ScanContext(ctx, "2006-W01-2"))
+	assert.True(t, time.Date(2006, 1, 2, 23, 0, 0, 0, time.UTC).Equal(time.Time(nt)))
+}
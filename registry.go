@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Registry decodes polymorphic JSON objects into one of several registered Go
+// types, chosen by the string value of a discriminator key.
+//
+//	r := types.NewRegistry("type")
+//	r.Register("circle", func() interface{} { return new(Circle) })
+//	r.Register("square", func() interface{} { return new(Square) })
+//	shape, err := r.Decode(types.JSONRawMessage(`{"type":"circle","r":2}`))
+//
+// It is safe for concurrent use.
+type Registry struct {
+	key string
+
+	mu        sync.RWMutex
+	factories map[string]func() interface{}
+}
+
+// NewRegistry returns an empty Registry reading the discriminator from key.
+func NewRegistry(key string) *Registry {
+	return &Registry{key: key, factories: map[string]func() interface{}{}}
+}
+
+// Register makes Decode use factory for objects whose discriminator is
+// typeName, replacing any previous registration. factory must return a new
+// pointer to decode into.
+func (r *Registry) Register(typeName string, factory func() interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[typeName] = factory
+}
+
+// Decode decodes m into the value returned by the factory registered for its
+// discriminator and returns that value. It returns an error if m is not an
+// object, has no string discriminator, or the discriminator is not registered.
+func (r *Registry) Decode(m JSONRawMessage) (interface{}, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(m.orNull(), &doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if doc == nil {
+		return nil, errors.New("types.Registry: Decode on a value which is not an object")
+	}
+	raw, ok := doc[r.key]
+	if !ok {
+		return nil, errors.Errorf("types.Registry: missing discriminator %q", r.key)
+	}
+	var typeName string
+	if err := json.Unmarshal(raw, &typeName); err != nil {
+		return nil, errors.Errorf("types.Registry: discriminator %q is %s, not a string", r.key, raw)
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[typeName]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("types.Registry: unknown %s %q", r.key, typeName)
+	}
+
+	v := factory()
+	if err := json.Unmarshal(m, v); err != nil {
+		return nil, errors.Wrapf(err, "types.Registry: unable to decode %q", typeName)
+	}
+	return v, nil
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	type circle struct {
+		Radius float64 `json:"r"`
+	}
+	type square struct {
+		Side float64 `json:"side"`
+	}
+
+	r := NewRegistry("kind")
+	r.Register("circle", func() interface{} { return new(circle) })
+	r.Register("square", func() interface{} { return new(square) })
+
+	v, err := r.Decode(JSONRawMessage(`{"kind":"circle","r":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, &circle{Radius: 2}, v)
+
+	v, err = r.Decode(JSONRawMessage(`{"side":3,"kind":"square"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &square{Side: 3}, v)
+
+	_, err = r.Decode(JSONRawMessage(`{"kind":"triangle"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown kind "triangle"`)
+
+	for _, in := range []string{``, `null`, `[]`, `{}`, `{"type":"circle"}`, `{"kind":1}`, `{"kind":"circle","r":"2"}`, `{`} {
+		_, err := r.Decode(JSONRawMessage(in))
+		assert.Error(t, err, "%s", in)
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// ScanAll scans the single JSON column of every row in rows and decodes it
+// into a T. NULL columns decode as JSON null. Rows are closed in any case.
+func ScanAll[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		var raw NullJSONRawMessage
+		if err := rows.Scan(&raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.Wrapf(err, "types: unable to decode row %d", len(result))
+		}
+		result = append(result, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanAll(t *testing.T) {
+	type doc struct {
+		Name string `json:"name"`
+	}
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT doc FROM docs").WillReturnRows(
+		sqlmock.NewRows([]string{"doc"}).
+			AddRow([]byte(`{"name":"a"}`)).
+			AddRow(`{"name":"b"}`).
+			AddRow(nil))
+	rows, err := db.Query("SELECT doc FROM docs")
+	require.NoError(t, err)
+
+	actual, err := ScanAll[*doc](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []*doc{{Name: "a"}, {Name: "b"}, nil}, actual)
+
+	mock.ExpectQuery("SELECT doc FROM docs").WillReturnRows(
+		sqlmock.NewRows([]string{"doc"}).AddRow(`{"name":1}`))
+	rows, err = db.Query("SELECT doc FROM docs")
+	require.NoError(t, err)
+	_, err = ScanAll[doc](rows)
+	require.Error(t, err)
+
+	mock.ExpectQuery("SELECT doc FROM docs").WillReturnRows(
+		sqlmock.NewRows([]string{"doc"}).AddRow(`{}`).RowError(0, errors.New("connection reset")))
+	rows, err = db.Query("SELECT doc FROM docs")
+	require.NoError(t, err)
+	_, err = ScanAll[doc](rows)
+	require.EqualError(t, err, "connection reset")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,10 +2,15 @@ package types
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,14 +32,23 @@ func (ns *NullString) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
-	return errors.WithStack(json.Unmarshal(data, ns))
+	return errors.WithStack(json.Unmarshal(data, (*string)(ns)))
 }
 
 // Scan implements the Scanner interface.
+//
+// Besides string and nil, Scan accepts the scalar values loosely-typed drivers
+// return: []byte is converted as-is, int64 in base 10, float64 in the shortest
+// representation (strconv.FormatFloat with 'g' and precision -1), bool as
+// "true" or "false", and time.Time as time.RFC3339Nano.
 func (ns *NullString) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
+	}
 	var v sql.NullString
 	if err := (&v).Scan(value); err != nil {
-		return err
+		return scanError("NullString", value, err)
 	}
 	*ns = NullString(v.String)
 	return nil
@@ -58,28 +72,124 @@ type NullTime time.Time
 
 // Scan implements the Scanner interface.
 func (ns *NullTime) Scan(value interface{}) error {
-	var v sql.NullTime
-	if err := (&v).Scan(value); err != nil {
-		return err
+	return ns.ScanContext(context.Background(), value)
+}
+
+// ScanContext is like Scan but parses strings without time zone information in
+// the location attached to ctx using WithLocation, falling back to UTC.
+func (ns *NullTime) ScanContext(ctx context.Context, value interface{}) error {
+	_, err := ns.scan(ctx, value)
+	return err
+}
+
+// ScanLayout is like Scan but also returns the layout which parsed a string
+// value, for troubleshooting NullTimeLayouts. The layout is time.RFC3339 for
+// RFC 3339 strings and empty for NULL and non-string values. NullTime cannot
+// carry the layout itself as it is a plain time.Time.
+func (ns *NullTime) ScanLayout(value interface{}) (string, error) {
+	return ns.scan(context.Background(), value)
+}
+
+func (ns *NullTime) scan(ctx context.Context, value interface{}) (string, error) {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = nil
 	}
-	*ns = NullTime(v.Time)
-	return nil
+	t, layout, err := scanTime(value, LocationFromContext(ctx))
+	if err != nil {
+		return "", scanError("NullTime", value, err)
+	}
+	// Strip the monotonic clock reading so that scanned values compare equal
+	// to their serialized and re-parsed counterparts.
+	t, err = clampTime(t.Round(0))
+	if err != nil {
+		return "", scanError("NullTime", value, err)
+	}
+	*ns = NullTime(t)
+	return layout, nil
 }
 
 // MarshalJSON returns m as the JSON encoding of m.
 func (ns NullTime) MarshalJSON() ([]byte, error) {
-	var t *time.Time
-	if !time.Time(ns).IsZero() {
-		tt := time.Time(ns)
-		t = &tt
+	t := time.Time(ns)
+	if t.IsZero() {
+		switch {
+		case NullTimeNullAsEpoch:
+			t = time.Unix(0, 0).UTC()
+		case NullTimeNullAsEmptyString:
+			return []byte(`""`), nil
+		default:
+			return []byte(jsonNull), nil
+		}
+	}
+	if NullTimeMarshalWholeSeconds {
+		t = t.Truncate(time.Second)
+	}
+	if NullTimeUnixSeconds {
+		return []byte(formatUnixSeconds(t)), nil
+	}
+	// Always render UTC times with a "Z" designator, regardless of the zone name.
+	if _, offset := t.Zone(); offset == 0 {
+		t = t.UTC()
+	}
+	if NullTimeFractionDigits >= 0 {
+		return json.Marshal(t.Format(fixedFractionLayout(NullTimeFractionDigits)))
 	}
 	return json.Marshal(t)
 }
 
 // UnmarshalJSON sets *m to a copy of data.
 func (ns *NullTime) UnmarshalJSON(data []byte) error {
+	if isNullTimeJSONToken(data) {
+		*ns = NullTime{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil && (s == "" || isNullTimeSentinel(s)) {
+		*ns = NullTime{}
+		return nil
+	}
+
+	var t time.Time
+	if trimmed := bytes.TrimSpace(data); NullTimeUnixSeconds && len(trimmed) > 0 && (trimmed[0] == '-' || (trimmed[0] >= '0' && trimmed[0] <= '9')) {
+		var err error
+		if t, err = parseUnixSeconds(string(trimmed)); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(data, &t); err != nil {
+		return errors.Wrapf(err, "types.NullTime: unable to decode %s as a time", bytes.TrimSpace(data))
+	}
+	if NullTimeNullAsEpoch && t.Equal(time.Unix(0, 0)) {
+		t = time.Time{}
+	}
+	*ns = NullTime(t)
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, which also allows
+// using NullTime as a JSON object key. NULL is encoded as the empty string and
+// other values use time.RFC3339Nano.
+func (ns NullTime) MarshalText() ([]byte, error) {
+	if ns.IsZero() {
+		return []byte{}, nil
+	}
+	t := time.Time(ns)
+	if _, offset := t.Zone(); offset == 0 {
+		t = t.UTC()
+	}
+	return t.MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. Empty text
+// and any of NullTimeNullStrings are treated as NULL.
+func (ns *NullTime) UnmarshalText(data []byte) error {
+	if len(data) == 0 || isNullTimeSentinel(string(data)) {
+		*ns = NullTime{}
+		return nil
+	}
+
 	var t time.Time
-	if err := json.Unmarshal(data, &t); err != nil {
+	if err := t.UnmarshalText(data); err != nil {
 		return err
 	}
 	*ns = NullTime(t)
@@ -91,29 +201,144 @@ func (ns NullTime) Value() (driver.Value, error) {
 	return sql.NullTime{Valid: !time.Time(ns).IsZero(), Time: time.Time(ns)}.Value()
 }
 
+// IsZero returns true if ns is NULL.
+func (ns NullTime) IsZero() bool {
+	return time.Time(ns).IsZero()
+}
+
+// Equal reports whether ns and other are both NULL or represent the same time
+// instant. Unlike ==, Equal ignores the location and monotonic clock reading.
+func (ns NullTime) Equal(other NullTime) bool {
+	if ns.IsZero() || other.IsZero() {
+		return ns.IsZero() == other.IsZero()
+	}
+	return time.Time(ns).Equal(time.Time(other))
+}
+
+// RawMessageMaxDepth, if positive, makes Scan, ScanNoCopy and UnmarshalJSON of
+// JSONRawMessage and NullJSONRawMessage, as well as the typesjsoniter decoders,
+// reject documents which are nested
+// deeper than this many objects and arrays, or which are not valid JSON, to
+// protect recursive parsers further downstream. The error wraps ErrJSONTooDeep.
+var RawMessageMaxDepth = 0
+
+// ErrJSONTooDeep is returned if a document exceeds RawMessageMaxDepth.
+var ErrJSONTooDeep = errors.New("types: JSON document is nested too deeply")
+
+func checkMaxDepth(raw []byte) error {
+	if RawMessageMaxDepth <= 0 {
+		return nil
+	}
+	depth, err := JSONRawMessage(raw).MaxDepth()
+	if err != nil {
+		return err
+	}
+	if depth > RawMessageMaxDepth {
+		return errors.Wrapf(ErrJSONTooDeep, "depth %d exceeds %d", depth, RawMessageMaxDepth)
+	}
+	return nil
+}
+
+// RawMessageValueBytes makes JSONRawMessage.Value and NullJSONRawMessage.Value
+// return []byte instead of string, which some drivers handle more efficiently.
+var RawMessageValueBytes = false
+
+func rawValue(b []byte) driver.Value {
+	if RawMessageValueBytes {
+		return b
+	}
+	return string(b)
+}
+
+// jsonNull is the JSON null literal.
+const jsonNull = "null"
+
+// isJSONNull reports whether b is empty or the JSON literal null, ignoring
+// insignificant whitespace. An empty raw message is treated as null throughout
+// this package. JSON literals are case-sensitive, so e.g. NULL is not null but
+// invalid JSON.
+func isJSONNull(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) == 0 || string(b) == jsonNull
+}
+
 // JSONRawMessage represents a json.RawMessage that works well with JSON, SQL, and Swagger.
 type JSONRawMessage json.RawMessage
 
-// Scan implements the Scanner interface.
+// Scan implements the Scanner interface. If m is empty but has capacity, e.g.
+// after Reset, the value is copied into its backing array.
 func (m *JSONRawMessage) Scan(value interface{}) error {
-	*m = []byte(fmt.Sprintf("%s", value))
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = jsonNull
+	}
+	var raw []byte
+	if len(*m) == 0 && cap(*m) > 0 {
+		raw = appendScanBytes((*m)[:0], value)
+	} else {
+		raw = scanBytes(value)
+	}
+	if err := checkMaxDepth(raw); err != nil {
+		return reportDecodeError(scanError("JSONRawMessage", value, err), raw)
+	}
+	*m = raw
 	return nil
 }
 
+// Reset empties m but keeps its backing array, so that a following Scan or
+// UnmarshalJSON reuses it instead of allocating. This is intended for pooled
+// values: the bytes of m are overwritten by the next use, so use DeepCopy for
+// bytes which must outlive the pool entry.
+func (m *JSONRawMessage) Reset() {
+	*m = (*m)[:0]
+}
+
 // Value implements the driver Valuer interface.
 func (m JSONRawMessage) Value() (driver.Value, error) {
-	if len(m) == 0 {
-		return "null", nil
+	if isJSONNull(m) {
+		return rawValue([]byte(jsonNull)), nil
 	}
-	return string(m), nil
+	return rawValue(m), nil
 }
 
 // MarshalJSON returns m as the JSON encoding of m.
+//
+// The returned slice aliases m. encoding/json copies it into its own buffer,
+// but callers invoking MarshalJSON directly must not modify the result; use
+// MarshalJSONCopy if the output needs to outlive or be mutated independently of m.
 func (m JSONRawMessage) MarshalJSON() ([]byte, error) {
-	if len(m) == 0 {
-		return []byte("null"), nil
+	if len(bytes.TrimSpace(m)) == 0 {
+		return []byte(JSONRawMessageEmptyJSON), nil
 	}
-	return m, nil
+	return m.orNull(), nil
+}
+
+// MarshalJSONCopy is like MarshalJSON but always returns a fresh copy of m.
+func (m JSONRawMessage) MarshalJSONCopy() ([]byte, error) {
+	raw, _ := m.MarshalJSON()
+	return append([]byte(nil), raw...), nil
+}
+
+// JSONRawMessageEmptyJSON is the JSON which JSONRawMessage.MarshalJSON returns
+// for an empty message, e.g. {} for schemas which require an object even if it
+// is absent. It must be valid JSON and defaults to null. Other methods, such as
+// Value, still treat empty messages as null.
+var JSONRawMessageEmptyJSON = jsonNull
+
+// orNull returns m, or null if m is empty or null.
+func (m JSONRawMessage) orNull() []byte {
+	if isJSONNull(m) {
+		return []byte(jsonNull)
+	}
+	return m
+}
+
+// String implements the Stringer interface. An empty message is "null".
+func (m JSONRawMessage) String() string {
+	if isJSONNull(m) {
+		return jsonNull
+	}
+	return string(m)
 }
 
 // UnmarshalJSON sets *m to a copy of data.
@@ -121,6 +346,9 @@ func (m *JSONRawMessage) UnmarshalJSON(data []byte) error {
 	if m == nil {
 		return errors.New("json.RawMessage: UnmarshalJSON on nil pointer")
 	}
+	if err := checkMaxDepth(data); err != nil {
+		return errors.Wrap(err, "types.JSONRawMessage")
+	}
 	*m = append((*m)[0:0], data...)
 	return nil
 }
@@ -130,25 +358,98 @@ type NullJSONRawMessage json.RawMessage
 
 // Scan implements the Scanner interface.
 func (m *NullJSONRawMessage) Scan(value interface{}) error {
-	if value == nil {
-		value = "null"
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = jsonNull
+	}
+	raw := scanBytes(value)
+	if err := checkMaxDepth(raw); err != nil {
+		return reportDecodeError(scanError("NullJSONRawMessage", value, err), raw)
 	}
-	*m = []byte(fmt.Sprintf("%s", value))
+	*m = raw
 	return nil
 }
 
-// Value implements the driver Valuer interface.
+// scanBytes returns a copy of the bytes of value. Values whose type is
+// convertible to []byte or string, e.g. json.RawMessage, are copied directly,
+// and all other values are formatted using fmt.
+func scanBytes(value interface{}) []byte {
+	return appendScanBytes([]byte{}, value)
+}
+
+// appendScanBytes is like scanBytes but appends the bytes to dst.
+func appendScanBytes(dst []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return append(dst, v...)
+	case string:
+		return append(dst, v...)
+	}
+
+	if v := reflect.ValueOf(value); v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			return append(dst, v.Bytes()...)
+		case v.Kind() == reflect.String:
+			return append(dst, v.String()...)
+		}
+	}
+	return append(dst, fmt.Sprintf("%s", value)...)
+}
+
+// NullJSONRawMessageCompactValue makes NullJSONRawMessage.Value strip insignificant
+// whitespace from valid JSON before storing it. Invalid JSON is stored as-is.
+var NullJSONRawMessageCompactValue = false
+
+// NullJSONRawMessageStoreAsSQLNull is the default of how NullJSONRawMessage.Value
+// stores empty messages and the JSON literal null. If true, which is the
+// default, they are stored as SQL NULL. If false, they are stored as the JSON
+// text null, which is required for NOT NULL columns. Use ValueSQLNull or
+// ValueJSONNull to choose per value, or JSONRawMessage for NOT NULL columns.
+var NullJSONRawMessageStoreAsSQLNull = true
+
+// Value implements the driver Valuer interface. Empty messages and null are
+// stored as configured by NullJSONRawMessageStoreAsSQLNull.
+//
+// Before NullJSONRawMessageStoreAsSQLNull was added, only empty messages were
+// stored as SQL NULL, while null, including null surrounded by whitespace, was
+// stored verbatim as JSON text.
 func (m NullJSONRawMessage) Value() (driver.Value, error) {
-	if len(m) == 0 {
-		return nil, nil
+	return m.value(NullJSONRawMessageStoreAsSQLNull)
+}
+
+// ValueSQLNull is like Value but stores empty messages and null as SQL NULL
+// regardless of NullJSONRawMessageStoreAsSQLNull.
+func (m NullJSONRawMessage) ValueSQLNull() (driver.Value, error) {
+	return m.value(true)
+}
+
+// ValueJSONNull is like Value but stores empty messages and null as the JSON
+// text null regardless of NullJSONRawMessageStoreAsSQLNull.
+func (m NullJSONRawMessage) ValueJSONNull() (driver.Value, error) {
+	return m.value(false)
+}
+
+func (m NullJSONRawMessage) value(storeAsSQLNull bool) (driver.Value, error) {
+	if isJSONNull(m) {
+		if storeAsSQLNull {
+			return nil, nil
+		}
+		return rawValue([]byte(jsonNull)), nil
 	}
-	return string(m), nil
+	if NullJSONRawMessageCompactValue {
+		var b bytes.Buffer
+		if err := json.Compact(&b, m); err == nil {
+			return rawValue(b.Bytes()), nil
+		}
+	}
+	return rawValue(m), nil
 }
 
 // MarshalJSON returns m as the JSON encoding of m.
 func (m NullJSONRawMessage) MarshalJSON() ([]byte, error) {
-	if len(m) == 0 {
-		return []byte("null"), nil
+	if isJSONNull(m) {
+		return []byte(jsonNull), nil
 	}
 	return m, nil
 }
@@ -158,24 +459,140 @@ func (m *NullJSONRawMessage) UnmarshalJSON(data []byte) error {
 	if m == nil {
 		return errors.New("json.RawMessage: UnmarshalJSON on nil pointer")
 	}
+	if err := checkMaxDepth(data); err != nil {
+		return errors.Wrap(err, "types.NullJSONRawMessage")
+	}
 	*m = append((*m)[0:0], data...)
 	return nil
 }
 
+// NewNullJSONRawMessage returns the JSON encoding of v as a NullJSONRawMessage.
+// If v is nil, the returned message is NULL.
+func NewNullJSONRawMessage(v interface{}) (NullJSONRawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// ScanPreprocessor transforms a raw column value before it is decoded as JSON.
+type ScanPreprocessor func(raw []byte) ([]byte, error)
+
+var scanPreprocessors sync.Map
+
+// RegisterScanPreprocessor registers fn to be applied by JSONScan to the raw column
+// value whenever the destination has the same type as dst. This allows reusing
+// JSONScan for custom column encodings, e.g. compressed JSON.
+func RegisterScanPreprocessor(dst interface{}, fn ScanPreprocessor) {
+	scanPreprocessors.Store(reflect.TypeOf(dst), fn)
+}
+
+// OnDecodeError, if set, is called with the error and the raw input whenever
+// JSONScan or a Scan method fails to decode a stored value, before the error is
+// returned. It can be used to monitor data quality and must not modify raw.
+var OnDecodeError func(err error, raw []byte)
+
+func reportDecodeError(err error, raw []byte) error {
+	if OnDecodeError != nil {
+		OnDecodeError(err, raw)
+	}
+	return err
+}
+
+// DecodeOptions configures the json.Decoder used by JSONScanWithOptions.
+type DecodeOptions struct {
+	// UseNumber decodes numbers into interface{} values as json.Number instead of float64.
+	UseNumber bool
+
+	// DisallowUnknownFields rejects objects with keys which do not match any
+	// non-ignored, exported field of the destination struct.
+	DisallowUnknownFields bool
+}
+
 // JSONScan is a generic helper for storing a value as a JSON blob in SQL.
 func JSONScan(dst interface{}, value interface{}) error {
-	if value == nil {
-		value = "null"
+	return JSONScanWithOptions(dst, value, DecodeOptions{})
+}
+
+// JSONScanWithOptions is like JSONScan but configures decoding using opts.
+func JSONScanWithOptions(dst interface{}, value interface{}, opts DecodeOptions) error {
+	value = unwrapSQLNull(value)
+	null := IsDriverNull(value)
+	if null {
+		value = jsonNull
+	}
+	raw := scanBytes(value)
+	if fn, ok := scanPreprocessors.Load(reflect.TypeOf(dst)); ok && !null {
+		var err error
+		original := raw
+		if raw, err = fn.(ScanPreprocessor)(raw); err != nil {
+			return reportDecodeError(fmt.Errorf("types: unable to preprocess payload: %w", err), original)
+		}
+	}
+	return jsonDecode(dst, raw, opts)
+}
+
+// JSONDecode is the decoding core of JSONScan. It decodes raw into dst without
+// applying any registered ScanPreprocessor.
+func JSONDecode(dst interface{}, raw []byte) error {
+	return jsonDecode(dst, raw, DecodeOptions{})
+}
+
+func jsonDecode(dst interface{}, raw []byte, opts DecodeOptions) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if opts.UseNumber {
+		dec.UseNumber()
 	}
-	if err := json.Unmarshal([]byte(fmt.Sprintf("%s", value)), &dst); err != nil {
-		return fmt.Errorf("unable to decode payload to: %s", err)
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&dst); err != nil {
+		return reportDecodeError(fmt.Errorf("types: unable to decode payload: %w", err), raw)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return reportDecodeError(errors.New("types: unable to decode payload: unexpected data after top-level value"), raw)
 	}
 	return nil
 }
 
+// ScanJSONInto decodes a driver value holding JSON into dst, which must be a
+// non-nil pointer. SQL NULL decodes as JSON null. Together with MarshalJSONValue
+// it is the stable building block for implementing sql.Scanner and driver.Valuer
+// on custom types:
+//
+//	func (d *Document) Scan(value interface{}) error { return types.ScanJSONInto(d, value) }
+//
+//	func (d Document) Value() (driver.Value, error) { return types.MarshalJSONValue(d) }
+func ScanJSONInto(dst interface{}, value interface{}) error {
+	if v := reflect.ValueOf(dst); v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("types: ScanJSONInto requires a non-nil pointer but got %T", dst)
+	}
+	return JSONScan(dst, value)
+}
+
+// MarshalJSONValue encodes src as a JSON driver value. A nil src or nil pointer
+// is stored as SQL NULL. See ScanJSONInto for the inverse.
+func MarshalJSONValue(src interface{}) (driver.Value, error) {
+	if isNilValue(src) {
+		return nil, nil
+	}
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return string(b), nil
+}
+
 // JSONValue is a generic helper for retrieving a SQL JSON-encoded value.
+//
+// A nil src or a nil pointer is stored as SQL NULL, while values which encode to
+// the JSON literal null, e.g. JSONRawMessage("null"), are stored as JSON text.
 func JSONValue(src interface{}) (driver.Value, error) {
-	if src == nil {
+	if isNilValue(src) {
 		return nil, nil
 	}
 	var b bytes.Buffer
@@ -184,3 +601,94 @@ func JSONValue(src interface{}) (driver.Value, error) {
 	}
 	return b.String(), nil
 }
+
+// JSONValueBytes is like JSONValue but returns the encoded value as []byte.
+//
+// database/sql requires driver values to be fully materialized, so a value can not
+// be streamed to the driver. JSONValueBytes instead avoids the additional copy
+// JSONValue makes when converting the encoded buffer to a string, which roughly
+// halves the peak memory needed for large documents. Unlike JSONValue, the result
+// has no trailing newline.
+func JSONValueBytes(src interface{}) (driver.Value, error) {
+	if isNilValue(src) {
+		return nil, nil
+	}
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// ValueTooLargeError is returned by LimitedJSONValue if the encoded value exceeds the limit.
+type ValueTooLargeError struct {
+	Size, Max int
+}
+
+func (e *ValueTooLargeError) Error() string {
+	return fmt.Sprintf("types: encoded JSON value is %d bytes which exceeds the limit of %d bytes", e.Size, e.Max)
+}
+
+// LimitedJSONValue is like JSONValue but returns a *ValueTooLargeError if the
+// encoded value is larger than max bytes. The trailing newline JSONValue
+// appends does not count towards the limit.
+func LimitedJSONValue(max int, src interface{}) (driver.Value, error) {
+	v, err := JSONValue(src)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := v.(string); ok {
+		if size := len(strings.TrimSuffix(s, "\n")); size > max {
+			return nil, errors.WithStack(&ValueTooLargeError{Size: size, Max: max})
+		}
+	}
+	return v, nil
+}
+
+// unwrapSQLNull returns the underlying driver value if value is one of the
+// database/sql Null types, which some ORMs pass to Scan instead of the plain
+// driver value. Invalid ones become nil.
+func unwrapSQLNull(value interface{}) interface{} {
+	switch value.(type) {
+	case sql.NullString, sql.NullInt64, sql.NullInt32, sql.NullInt16, sql.NullByte,
+		sql.NullFloat64, sql.NullBool, sql.NullTime:
+		v, _ := value.(driver.Valuer).Value()
+		return v
+	}
+	return value
+}
+
+// scanError wraps an error returned from the Scan method of the type called
+// name. Errors from every Scan method in this package are prefixed with
+// "types: ", name the dynamic type of the driver value, and keep err in the
+// chain for errors.Is and errors.As.
+func scanError(name string, value interface{}, err error) error {
+	from := "nil"
+	if typ := reflect.TypeOf(value); typ != nil {
+		from = typ.String()
+	}
+	return fmt.Errorf("types: unable to scan into %s from %s: %w", name, from, err)
+}
+
+// IsDriverNull reports whether value, as passed to a Scan method, represents SQL
+// NULL. This is the case for nil as well as typed nil pointers, slices such as
+// ([]byte)(nil), and other nil values stored in the interface.
+func IsDriverNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isNilValue reports whether src is nil or a nil pointer.
+func isNilValue(src interface{}) bool {
+	if src == nil {
+		return true
+	}
+	v := reflect.ValueOf(src)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
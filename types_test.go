@@ -1,8 +1,17 @@
 package types
 
 import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,3 +22,491 @@ func TestNullTime(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, "null", string(out))
 }
+
+func TestJSONRawMessageMarshalJSONCopy(t *testing.T) {
+	m := JSONRawMessage(`{"a":1}`)
+
+	out, err := m.MarshalJSONCopy()
+	require.NoError(t, err)
+	out[0] = '['
+	assert.EqualValues(t, `{"a":1}`, string(m))
+
+	out, err = JSONRawMessage(nil).MarshalJSONCopy()
+	require.NoError(t, err)
+	assert.EqualValues(t, "null", string(out))
+}
+
+func TestNewNullJSONRawMessage(t *testing.T) {
+	m, err := NewNullJSONRawMessage(nil)
+	require.NoError(t, err)
+	v, err := m.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	m, err = NewNullJSONRawMessage(struct {
+		Foo string `json:"foo"`
+	}{Foo: "bar"})
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"foo":"bar"}`, string(m))
+
+	_, err = NewNullJSONRawMessage(make(chan int))
+	require.Error(t, err)
+}
+
+func TestJSONValueBytes(t *testing.T) {
+	v, err := JSONValueBytes(nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = JSONValueBytes(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.EqualValues(t, []byte(`{"a":1}`), v)
+}
+
+func benchmarkDocument() map[string]string {
+	doc := make(map[string]string, 1000)
+	for i := 0; i < 1000; i++ {
+		doc[fmt.Sprintf("key-%d", i)] = strings.Repeat("x", 1000)
+	}
+	return doc
+}
+
+func BenchmarkJSONValue(b *testing.B) {
+	doc := benchmarkDocument()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := JSONValue(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONValueBytes(b *testing.B) {
+	doc := benchmarkDocument()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := JSONValueBytes(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type gzipDocument struct {
+	Foo string `json:"foo"`
+}
+
+func (d *gzipDocument) Scan(value interface{}) error {
+	return JSONScan(d, value)
+}
+
+func TestRegisterScanPreprocessor(t *testing.T) {
+	RegisterScanPreprocessor(new(gzipDocument), func(raw []byte) ([]byte, error) {
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(r)
+	})
+
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write([]byte(`{"foo":"bar"}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	var actual gzipDocument
+	require.NoError(t, actual.Scan(b.Bytes()))
+	assert.Equal(t, "bar", actual.Foo)
+
+	require.Error(t, actual.Scan([]byte(`{"foo":"bar"}`)))
+	require.NoError(t, actual.Scan(nil))
+	require.NoError(t, actual.Scan([]byte(nil)))
+	require.NoError(t, actual.Scan(sql.NullString{}))
+
+	// Only driver NULL skips the preprocessor, regardless of the value's type.
+	require.Error(t, actual.Scan("null"))
+	require.Error(t, actual.Scan([]byte("null")))
+}
+
+func TestNullJSONRawMessageCompactValue(t *testing.T) {
+	m := NullJSONRawMessage("{\n  \"a\": [1, 2]\n}")
+
+	v, err := m.Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, string(m), v)
+
+	NullJSONRawMessageCompactValue = true
+	defer func() { NullJSONRawMessageCompactValue = false }()
+
+	v, err = m.Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":[1,2]}`, v)
+
+	v, err = NullJSONRawMessage("{not json ").Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, "{not json ", v)
+}
+
+func TestJSONValueNil(t *testing.T) {
+	type foo struct{ Bar string }
+
+	v, err := JSONValue(nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = JSONValue((*foo)(nil))
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = JSONValueBytes((*foo)(nil))
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = JSONValue(&foo{Bar: "baz"})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"Bar\":\"baz\"}\n", v)
+
+	v, err = JSONValue(JSONRawMessage("null"))
+	require.NoError(t, err)
+	assert.Equal(t, "null\n", v)
+}
+
+func TestLimitedJSONValue(t *testing.T) {
+	doc := map[string]string{"foo": strings.Repeat("x", 100)}
+
+	v, err := LimitedJSONValue(1024, doc)
+	require.NoError(t, err)
+	assert.NotNil(t, v)
+
+	_, err = LimitedJSONValue(64, doc)
+	var e *ValueTooLargeError
+	require.True(t, errors.As(err, &e), "%+v", err)
+	assert.Equal(t, 64, e.Max)
+	assert.Equal(t, 110, e.Size)
+
+	v, err = LimitedJSONValue(7, map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n", v)
+	_, err = LimitedJSONValue(6, map[string]int{"a": 1})
+	require.True(t, errors.As(err, &e), "%+v", err)
+	assert.Equal(t, 7, e.Size)
+
+	v, err = LimitedJSONValue(0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestJSONScanWithOptions(t *testing.T) {
+	type doc struct {
+		N interface{} `json:"n"`
+	}
+	const in = `{"n":1,"extra":true}`
+
+	for _, tc := range []struct {
+		opts      DecodeOptions
+		expectErr bool
+		expect    interface{}
+	}{
+		{opts: DecodeOptions{}, expect: float64(1)},
+		{opts: DecodeOptions{UseNumber: true}, expect: json.Number("1")},
+		{opts: DecodeOptions{DisallowUnknownFields: true}, expectErr: true},
+		{opts: DecodeOptions{UseNumber: true, DisallowUnknownFields: true}, expectErr: true},
+	} {
+		t.Run(fmt.Sprintf("opts=%+v", tc.opts), func(t *testing.T) {
+			var actual doc
+			err := JSONScanWithOptions(&actual, in, tc.opts)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, actual.N)
+		})
+	}
+
+	var actual doc
+	require.NoError(t, JSONScanWithOptions(&actual, `{"n":1}`, DecodeOptions{UseNumber: true, DisallowUnknownFields: true}))
+	assert.Equal(t, json.Number("1"), actual.N)
+
+	require.NoError(t, JSONScan(&actual, []byte(in)))
+	assert.Equal(t, float64(1), actual.N)
+	require.Error(t, JSONScan(&actual, `{}{}`))
+}
+
+func TestRawMessageValueBytes(t *testing.T) {
+	for _, tc := range []struct {
+		valueBytes bool
+		expect     interface{}
+	}{
+		{valueBytes: false, expect: `{"a":1}`},
+		{valueBytes: true, expect: []byte(`{"a":1}`)},
+	} {
+		t.Run(fmt.Sprintf("bytes=%v", tc.valueBytes), func(t *testing.T) {
+			RawMessageValueBytes = tc.valueBytes
+			defer func() { RawMessageValueBytes = false }()
+
+			v, err := JSONRawMessage(`{"a":1}`).Value()
+			require.NoError(t, err)
+			assert.IsType(t, tc.expect, v)
+			assert.EqualValues(t, tc.expect, v)
+
+			v, err = NullJSONRawMessage(`{"a":1}`).Value()
+			require.NoError(t, err)
+			assert.IsType(t, tc.expect, v)
+			assert.EqualValues(t, tc.expect, v)
+		})
+	}
+}
+
+func TestOnDecodeError(t *testing.T) {
+	var calls []string
+	OnDecodeError = func(err error, raw []byte) {
+		require.Error(t, err)
+		calls = append(calls, string(raw))
+	}
+	defer func() { OnDecodeError = nil }()
+
+	var v map[string]interface{}
+	require.NoError(t, JSONScan(&v, `{"a":1}`))
+	assert.Empty(t, calls)
+
+	require.Error(t, JSONScan(&v, `{"a":`))
+	assert.Equal(t, []string{`{"a":`}, calls)
+
+	var b Base64JSONRawMessage
+	require.Error(t, b.Scan("!!"))
+	assert.Equal(t, []string{`{"a":`, "!!"}, calls)
+
+	var validated ValidatedJSONRawMessage[StrictJSON]
+	require.NoError(t, validated.Scan([]byte(`{"a":1}`)))
+	require.Error(t, validated.Scan([]byte(`{}{}`)))
+	assert.Equal(t, []string{`{"a":`, "!!", `{}{}`}, calls)
+
+	var h HStore
+	require.NoError(t, h.Scan(`"a"=>"1"`))
+	require.Error(t, h.Scan(`"a"=>`))
+	assert.Equal(t, []string{`{"a":`, "!!", `{}{}`, `"a"=>`}, calls)
+
+	OnDecodeError = nil
+	require.Error(t, JSONScan(&v, `{"a":`))
+	assert.Len(t, calls, 4)
+}
+
+func TestNullStringScan(t *testing.T) {
+	for _, tc := range []struct {
+		in     interface{}
+		expect NullString
+	}{
+		{in: nil, expect: ""},
+		{in: "foo", expect: "foo"},
+		{in: []byte("bar"), expect: "bar"},
+		{in: int64(-42), expect: "-42"},
+		{in: float64(1.5), expect: "1.5"},
+		{in: float64(1e21), expect: "1e+21"},
+		{in: true, expect: "true"},
+		{in: false, expect: "false"},
+		{in: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), expect: "2021-01-01T00:00:00Z"},
+	} {
+		t.Run(fmt.Sprintf("in=%#v", tc.in), func(t *testing.T) {
+			ns := NullString("previous")
+			require.NoError(t, ns.Scan(tc.in))
+			assert.Equal(t, tc.expect, ns)
+		})
+	}
+}
+
+func TestIsDriverNull(t *testing.T) {
+	assert.True(t, IsDriverNull(nil))
+	assert.True(t, IsDriverNull([]byte(nil)))
+	assert.True(t, IsDriverNull((*string)(nil)))
+	assert.True(t, IsDriverNull(json.RawMessage(nil)))
+	assert.False(t, IsDriverNull([]byte{}))
+	assert.False(t, IsDriverNull(""))
+	assert.False(t, IsDriverNull(int64(0)))
+
+	for _, in := range []interface{}{nil, []byte(nil), (*time.Time)(nil)} {
+		t.Run(fmt.Sprintf("in=%#v", in), func(t *testing.T) {
+			ns := NullString("foo")
+			require.NoError(t, ns.Scan(in))
+			assert.Empty(t, ns)
+
+			nt := NullTime(time.Now())
+			require.NoError(t, nt.Scan(in))
+			assert.True(t, nt.IsZero())
+
+			ni := NullInt64{Int64: 1, Valid: true}
+			require.NoError(t, ni.Scan(in))
+			assert.False(t, ni.Valid)
+
+			var raw JSONRawMessage
+			require.NoError(t, raw.Scan(in))
+			assert.Equal(t, "null", string(raw))
+
+			var null NullJSONRawMessage
+			require.NoError(t, null.Scan(in))
+			assert.Equal(t, "null", string(null))
+
+			var v *struct{}
+			require.NoError(t, JSONScan(&v, in))
+			assert.Nil(t, v)
+		})
+	}
+}
+
+type customDocument struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func (d *customDocument) Scan(value interface{}) error {
+	return ScanJSONInto(d, value)
+}
+
+func (d customDocument) Value() (driver.Value, error) {
+	return MarshalJSONValue(d)
+}
+
+func TestScanJSONIntoMarshalJSONValue(t *testing.T) {
+	in := customDocument{Name: "foo", Tags: []string{"a", "b"}}
+
+	v, err := in.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"foo","tags":["a","b"]}`, v)
+
+	for _, stored := range []interface{}{v, []byte(v.(string)), json.RawMessage(v.(string))} {
+		var actual customDocument
+		require.NoError(t, actual.Scan(stored))
+		assert.Equal(t, in, actual)
+	}
+
+	v, err = MarshalJSONValue((*customDocument)(nil))
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	var ptr *customDocument
+	require.NoError(t, ScanJSONInto(&ptr, nil))
+	assert.Nil(t, ptr)
+
+	require.Error(t, ScanJSONInto(customDocument{}, v))
+	require.Error(t, ScanJSONInto((*customDocument)(nil), v))
+	require.Error(t, new(customDocument).Scan(`{"name":1}`))
+}
+
+func TestNullStringUnmarshalJSON(t *testing.T) {
+	var ns NullString
+	require.NoError(t, json.Unmarshal([]byte(`"foo"`), &ns))
+	assert.Equal(t, NullString("foo"), ns)
+	require.Error(t, json.Unmarshal([]byte(`1`), &ns))
+}
+
+func TestScanErrorPrefix(t *testing.T) {
+	for k, tc := range []struct {
+		dst sql.Scanner
+		in  interface{}
+	}{
+		{dst: new(NullString), in: struct{}{}},
+		{dst: new(NullInt64), in: "abc"},
+		{dst: new(NullTime), in: true},
+		{dst: new(NullTimeOfDay), in: int64(1)},
+		{dst: new(NullBytes), in: int64(1)},
+		{dst: new(Base64JSONRawMessage), in: "!"},
+		{dst: new(HStore), in: 1.5},
+		{dst: new(NullMoney), in: "12.34"},
+	} {
+		t.Run(fmt.Sprintf("case=%d/type=%T", k, tc.dst), func(t *testing.T) {
+			err := tc.dst.Scan(tc.in)
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), "types: unable to scan into "), "%s", err)
+			assert.Contains(t, err.Error(), fmt.Sprintf(" from %T: ", tc.in))
+		})
+	}
+
+	var nt NullTime
+	err := nt.Scan(sql.NullString{String: "garbage", Valid: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "types: unable to scan into NullTime from string: ")
+
+	var p NullPtr[int]
+	err = p.Scan([]byte(`{`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "types: unable to scan into NullPtr from []uint8: ")
+}
+
+func TestScanUnwrapsSQLNull(t *testing.T) {
+	var m JSONRawMessage
+	require.NoError(t, m.Scan(sql.NullString{Valid: true, String: `{}`}))
+	assert.Equal(t, `{}`, string(m))
+	require.NoError(t, m.Scan(sql.NullString{String: `{}`}))
+	assert.Equal(t, `null`, string(m))
+
+	var nm NullJSONRawMessage
+	require.NoError(t, nm.Scan(sql.NullString{}))
+	assert.Equal(t, `null`, string(nm))
+
+	now := time.Now().UTC().Round(0)
+	var nt NullTime
+	require.NoError(t, nt.Scan(sql.NullTime{Valid: true, Time: now}))
+	assert.True(t, now.Equal(time.Time(nt)))
+	require.NoError(t, nt.Scan(sql.NullTime{Time: now}))
+	assert.True(t, nt.IsZero())
+
+	var ni NullInt64
+	require.NoError(t, ni.Scan(sql.NullInt64{Valid: true, Int64: 42}))
+	assert.Equal(t, NullInt64{Int64: 42, Valid: true}, ni)
+
+	var ns NullString
+	require.NoError(t, ns.Scan(sql.NullBool{Valid: true, Bool: true}))
+	assert.Equal(t, NullString("true"), ns)
+}
+
+func TestJSONNullLiterals(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		isNull bool
+	}{
+		{in: ``, isNull: true},
+		{in: `null`, isNull: true},
+		{in: ` null `, isNull: true},
+		{in: "\n\tnull\r\n", isNull: true},
+		{in: `   `, isNull: true},
+		// JSON literals are case-sensitive, so these are invalid JSON and not null.
+		{in: `NULL`, isNull: false},
+		{in: `Null`, isNull: false},
+		{in: `"null"`, isNull: false},
+		{in: `nullx`, isNull: false},
+	} {
+		t.Run(fmt.Sprintf("in=%q", tc.in), func(t *testing.T) {
+			assert.Equal(t, tc.isNull, isJSONNull([]byte(tc.in)))
+
+			out, err := JSONRawMessage(tc.in).MarshalJSON()
+			require.NoError(t, err)
+			v, err := JSONRawMessage(tc.in).Value()
+			require.NoError(t, err)
+			nv, err := NullJSONRawMessage(tc.in).Value()
+			require.NoError(t, err)
+			nout, err := NullJSONRawMessage(tc.in).MarshalJSON()
+			require.NoError(t, err)
+			if tc.isNull {
+				assert.Equal(t, `null`, string(out))
+				assert.Equal(t, `null`, v)
+				assert.Nil(t, nv)
+				assert.Equal(t, `null`, string(nout))
+			} else {
+				assert.Equal(t, tc.in, string(out))
+				assert.Equal(t, tc.in, v)
+				assert.Equal(t, tc.in, nv)
+				assert.Equal(t, tc.in, string(nout))
+			}
+
+			var ni NullInt64
+			if tc.isNull {
+				require.NoError(t, ni.UnmarshalJSON([]byte(tc.in)))
+				assert.False(t, ni.Valid)
+			} else {
+				require.Error(t, ni.UnmarshalJSON([]byte(tc.in)))
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Package typesjsoniter registers fast github.com/json-iterator/go codecs for
+// the types of github.com/jkgx/types.
+//
+// Call Register once, e.g. in an init function, before using jsoniter:
+//
+//	func init() {
+//		typesjsoniter.Register()
+//	}
+//
+//	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+package typesjsoniter
+
+import (
+	"bytes"
+	"encoding/json"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/jkgx/types"
+)
+
+// Register registers the codecs for types.NullTime, types.JSONRawMessage, and
+// types.NullJSONRawMessage with jsoniter. The codecs produce the same output as
+// encoding/json, including omitempty and compaction of insignificant
+// whitespace and rejection of invalid JSON, but bypass jsoniter's generic
+// json.Marshaler handling, which copies the output.
+func Register() {
+	jsoniter.RegisterTypeEncoderFunc("types.NullTime", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		out, err := (*types.NullTime)(ptr).MarshalJSON()
+		if err != nil {
+			stream.Error = err
+			return
+		}
+		stream.WriteRaw(string(out))
+	}, nil)
+	jsoniter.RegisterTypeDecoderFunc("types.NullTime", func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		if err := (*types.NullTime)(ptr).UnmarshalJSON(iter.SkipAndReturnBytes()); err != nil {
+			iter.ReportError("decode types.NullTime", err.Error())
+		}
+	})
+
+	jsoniter.RegisterTypeEncoderFunc("types.JSONRawMessage", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		out, _ := (*types.JSONRawMessage)(ptr).MarshalJSON()
+		writeCompact(stream, out)
+	}, isEmptyBytes)
+	jsoniter.RegisterTypeDecoderFunc("types.JSONRawMessage", func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		if err := (*types.JSONRawMessage)(ptr).UnmarshalJSON(iter.SkipAndReturnBytes()); err != nil {
+			iter.ReportError("decode types.JSONRawMessage", err.Error())
+		}
+	})
+
+	jsoniter.RegisterTypeEncoderFunc("types.NullJSONRawMessage", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		out, _ := (*types.NullJSONRawMessage)(ptr).MarshalJSON()
+		writeCompact(stream, out)
+	}, isEmptyBytes)
+	jsoniter.RegisterTypeDecoderFunc("types.NullJSONRawMessage", func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		if err := (*types.NullJSONRawMessage)(ptr).UnmarshalJSON(iter.SkipAndReturnBytes()); err != nil {
+			iter.ReportError("decode types.NullJSONRawMessage", err.Error())
+		}
+	})
+}
+
+// isEmptyBytes reports whether the byte slice at ptr is empty, matching how
+// encoding/json treats omitempty for slices.
+func isEmptyBytes(ptr unsafe.Pointer) bool {
+	return len(*(*[]byte)(ptr)) == 0
+}
+
+// writeCompact writes out without insignificant whitespace, as encoding/json
+// does with the output of MarshalJSON. If out is not valid JSON, it sets
+// stream.Error and writes nothing.
+func writeCompact(stream *jsoniter.Stream, out []byte) {
+	var b bytes.Buffer
+	if err := json.Compact(&b, out); err != nil {
+		stream.Error = err
+		return
+	}
+	stream.Write(b.Bytes())
+}
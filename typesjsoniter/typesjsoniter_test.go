@@ -0,0 +1,98 @@
+package typesjsoniter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jkgx/types"
+)
+
+func TestRegister(t *testing.T) {
+	Register()
+
+	type doc struct {
+		CreatedAt types.NullTime           `json:"created_at"`
+		DeletedAt types.NullTime           `json:"deleted_at"`
+		Raw       types.JSONRawMessage     `json:"raw"`
+		Empty     types.JSONRawMessage     `json:"empty"`
+		Null      types.NullJSONRawMessage `json:"null,omitempty"`
+	}
+	in := doc{
+		CreatedAt: types.NullTime(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)),
+		Raw:       types.JSONRawMessage(`{"a":[1,2]}`),
+	}
+
+	out, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(in)
+	require.NoError(t, err)
+	expected, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(out))
+
+	var actual doc
+	require.NoError(t, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(out, &actual))
+	assert.Equal(t, in.CreatedAt, actual.CreatedAt)
+	assert.True(t, actual.DeletedAt.IsZero())
+	assert.Equal(t, in.Raw, actual.Raw)
+	assert.Equal(t, types.JSONRawMessage("null"), actual.Empty)
+
+	require.Error(t, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"created_at":"yesterday"}`), &actual))
+}
+
+func TestRegisterMaxDepth(t *testing.T) {
+	Register()
+
+	types.RawMessageMaxDepth = 1
+	defer func() { types.RawMessageMaxDepth = 0 }()
+
+	var doc struct {
+		Raw  types.JSONRawMessage     `json:"raw"`
+		Null types.NullJSONRawMessage `json:"null"`
+	}
+	require.Error(t, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"raw":[[1]]}`), &doc))
+	require.Error(t, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"null":{"a":{}}}`), &doc))
+	require.NoError(t, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"raw":[1],"null":{"a":1}}`), &doc))
+	assert.Equal(t, types.JSONRawMessage(`[1]`), doc.Raw)
+	assert.Equal(t, types.NullJSONRawMessage(`{"a":1}`), doc.Null)
+}
+
+func TestRegisterMatchesEncodingJSON(t *testing.T) {
+	Register()
+
+	type doc struct {
+		Raw  types.JSONRawMessage     `json:"r,omitempty"`
+		Null types.NullJSONRawMessage `json:"n,omitempty"`
+	}
+	for _, in := range []doc{
+		{},
+		{Raw: types.JSONRawMessage{}, Null: types.NullJSONRawMessage{}},
+		{Raw: types.JSONRawMessage(" {\"a\" : [1, 2],\n\t\"b\": \"x y\"} ")},
+		{Null: types.NullJSONRawMessage("[ 1,\r\n2 ]")},
+		{Raw: types.JSONRawMessage(" "), Null: types.NullJSONRawMessage("null")},
+	} {
+		expected, err := json.Marshal(in)
+		require.NoError(t, err)
+		out, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(in)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(out))
+	}
+}
+
+func TestRegisterInvalidJSON(t *testing.T) {
+	Register()
+
+	for _, in := range []interface{}{
+		struct{ Raw types.JSONRawMessage }{Raw: types.JSONRawMessage(`{`)},
+		struct{ Raw types.JSONRawMessage }{Raw: types.JSONRawMessage(`{"a": `)},
+		struct{ Null types.NullJSONRawMessage }{Null: types.NullJSONRawMessage(`[1,]`)},
+	} {
+		_, err := json.Marshal(in)
+		require.Error(t, err)
+		_, err = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(in)
+		require.Error(t, err, "%+v", in)
+	}
+}
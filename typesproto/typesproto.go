@@ -0,0 +1,27 @@
+// Package typesproto converts between github.com/jkgx/types and Protocol Buffers
+// well-known types.
+package typesproto
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jkgx/types"
+)
+
+// FromProtoTimestamp converts ts to a types.NullTime. A nil ts is NULL.
+func FromProtoTimestamp(ts *timestamppb.Timestamp) types.NullTime {
+	if ts == nil {
+		return types.NullTime{}
+	}
+	return types.NullTime(ts.AsTime())
+}
+
+// ToProtoTimestamp converts ns to a *timestamppb.Timestamp. A NULL ns is nil.
+func ToProtoTimestamp(ns types.NullTime) *timestamppb.Timestamp {
+	if ns.IsZero() {
+		return nil
+	}
+	return timestamppb.New(time.Time(ns))
+}
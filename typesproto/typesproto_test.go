@@ -0,0 +1,24 @@
+package typesproto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jkgx/types"
+)
+
+func TestProtoTimestamp(t *testing.T) {
+	assert.Nil(t, ToProtoTimestamp(types.NullTime{}))
+	assert.True(t, FromProtoTimestamp(nil).IsZero())
+
+	now := types.NullTime(time.Now())
+	ts := ToProtoTimestamp(now)
+	assert.Equal(t, time.Time(now).UnixNano(), ts.AsTime().UnixNano())
+	assert.True(t, now.Equal(FromProtoTimestamp(ts)))
+
+	epoch := FromProtoTimestamp(&timestamppb.Timestamp{})
+	assert.True(t, time.Unix(0, 0).Equal(time.Time(epoch)))
+}
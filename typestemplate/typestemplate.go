@@ -0,0 +1,29 @@
+// Package typestemplate helps rendering github.com/jkgx/types values with html/template.
+package typestemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+
+	"github.com/jkgx/types"
+)
+
+// JS returns m compacted and marked safe for embedding in a script context,
+// for example as a template function:
+//
+//	tmpl.Funcs(template.FuncMap{"js": typestemplate.JS})
+//	<script>var config = {{js .Config}};</script>
+//
+// The characters <, >, & and U+2028/U+2029 are escaped inside JSON strings so
+// that the output cannot terminate the surrounding script element. An empty or
+// invalid message is rendered as null.
+func JS(m types.JSONRawMessage) template.JS {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, m); err != nil {
+		return template.JS("null")
+	}
+	var escaped bytes.Buffer
+	json.HTMLEscape(&escaped, compacted.Bytes())
+	return template.JS(escaped.String())
+}
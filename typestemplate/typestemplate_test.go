@@ -0,0 +1,37 @@
+package typestemplate
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jkgx/types"
+)
+
+func TestJS(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(template.FuncMap{"js": JS}).Parse(`<script>var v = {{js .}};</script>`))
+
+	for _, tc := range []struct {
+		in     types.JSONRawMessage
+		expect string
+	}{
+		{in: types.JSONRawMessage(`{ "a": [1, 2] }`), expect: `<script>var v = {"a":[1,2]};</script>`},
+		{in: types.JSONRawMessage(`{"a":"</script><b>&"}`), expect: `<script>var v = {"a":"\u003c/script\u003e\u003cb\u003e\u0026"};</script>`},
+		{in: nil, expect: `<script>var v = null;</script>`},
+		{in: types.JSONRawMessage(`{`), expect: `<script>var v = null;</script>`},
+	} {
+		var out strings.Builder
+		require.NoError(t, tmpl.Execute(&out, tc.in))
+		assert.Equal(t, tc.expect, out.String())
+	}
+}
+
+func TestJSONRawMessageString(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`<p>{{.}}</p>`))
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, types.JSONRawMessage(`{"a":1}`)))
+	assert.Equal(t, `<p>{&#34;a&#34;:1}</p>`, out.String())
+}
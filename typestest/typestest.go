@@ -0,0 +1,159 @@
+// Package typestest provides helpers for testing code which uses github.com/jkgx/types.
+package typestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/jkgx/types"
+)
+
+// NullTimeComparer returns a cmp.Option which compares types.NullTime values
+// using types.NullTime.Equal, ignoring locations and monotonic clock readings.
+func NullTimeComparer() cmp.Option {
+	return cmp.Comparer(func(a, b types.NullTime) bool {
+		return a.Equal(b)
+	})
+}
+
+// RandomNullTime returns a random types.NullTime between 1970 and 2100 in UTC.
+// About one in five values is NULL.
+func RandomNullTime(rng *rand.Rand) types.NullTime {
+	if rng.Intn(5) == 0 {
+		return types.NullTime{}
+	}
+	min, max := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(), time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	return types.NullTime(time.Unix(0, min+rng.Int63n(max-min)).UTC())
+}
+
+// RandomJSONRawMessage returns a random, valid JSON document whose objects and
+// arrays are nested at most depth levels deep.
+func RandomJSONRawMessage(rng *rand.Rand, depth int) types.JSONRawMessage {
+	out, err := json.Marshal(randomJSON(rng, depth))
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func randomJSON(rng *rand.Rand, depth int) interface{} {
+	kinds := 4
+	if depth > 0 {
+		kinds = 6
+	}
+	switch rng.Intn(kinds) {
+	case 0:
+		return nil
+	case 1:
+		return rng.Intn(2) == 0
+	case 2:
+		return rng.NormFloat64() * 1000
+	case 3:
+		return randomString(rng)
+	case 4:
+		v := make([]interface{}, rng.Intn(4))
+		for i := range v {
+			v[i] = randomJSON(rng, depth-1)
+		}
+		return v
+	default:
+		v := make(map[string]interface{})
+		for i := rng.Intn(4); i > 0; i-- {
+			v[randomString(rng)] = randomJSON(rng, depth-1)
+		}
+		return v
+	}
+}
+
+func randomString(rng *rand.Rand) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 _-\"\\/äöü€😀"
+	runes := []rune(alphabet)
+	s := make([]rune, rng.Intn(10))
+	for i := range s {
+		s[i] = runes[rng.Intn(len(runes))]
+	}
+	return string(s)
+}
+
+// RoundTripJSON marshals v to JSON, unmarshals the result into a new value of
+// the same type, and fails the test if the two values differ. types.NullTime
+// values are compared using NullTimeComparer. v must not be nil or a nil
+// pointer.
+func RoundTripJSON(t testing.TB, v interface{}) {
+	t.Helper()
+
+	if rv := reflect.ValueOf(v); !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil() {
+		t.Fatalf("RoundTripJSON called with nil %T", v)
+		return
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unable to marshal %#v: %s", v, err)
+		return
+	}
+
+	actual := reflect.New(reflect.TypeOf(v))
+	if err := json.Unmarshal(out, actual.Interface()); err != nil {
+		t.Fatalf("unable to unmarshal %s into %T: %s", out, v, err)
+		return
+	}
+
+	if diff := cmp.Diff(v, actual.Elem().Interface(), NullTimeComparer()); diff != "" {
+		t.Errorf("JSON round trip of %T through %s changed the value (-want +got):\n%s", v, out, diff)
+	}
+}
+
+// AssertJSONEqual fails the test unless got and want represent the same JSON
+// value, ignoring insignificant whitespace, object key order, and the notation
+// of numbers, e.g. 1 equals 1.0. The failure message contains both documents
+// and a diff. An empty got is treated as null.
+func AssertJSONEqual(t testing.TB, got types.JSONRawMessage, want string) {
+	t.Helper()
+
+	raw, _ := got.MarshalJSON()
+	gotValue, err := decodeJSON(raw)
+	if err != nil {
+		t.Fatalf("got invalid JSON %s: %s", raw, err)
+		return
+	}
+	wantValue, err := decodeJSON([]byte(want))
+	if err != nil {
+		t.Fatalf("want invalid JSON %s: %s", want, err)
+		return
+	}
+
+	if diff := cmp.Diff(wantValue, gotValue, jsonNumberComparer()); diff != "" {
+		t.Errorf("JSON documents differ\ngot:  %s\nwant: %s\ndiff (-want +got):\n%s", raw, want, diff)
+	}
+}
+
+func decodeJSON(raw []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+func jsonNumberComparer() cmp.Option {
+	return cmp.Comparer(func(a, b json.Number) bool {
+		x, okx := new(big.Rat).SetString(a.String())
+		y, oky := new(big.Rat).SetString(b.String())
+		return okx && oky && x.Cmp(y) == 0
+	})
+}
@@ -0,0 +1,152 @@
+package typestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jkgx/types"
+)
+
+func TestNullTimeComparer(t *testing.T) {
+	type row struct {
+		CreatedAt types.NullTime
+	}
+
+	now := time.Now()
+	assert.True(t, cmp.Equal(row{types.NullTime(now)}, row{types.NullTime(now.Round(0))}, NullTimeComparer()))
+	assert.False(t, cmp.Equal(row{types.NullTime(now)}, row{}, NullTimeComparer()))
+}
+
+func TestRandomNullTime(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var nulls int
+	for i := 0; i < 100; i++ {
+		nt := RandomNullTime(rng)
+		if nt.IsZero() {
+			nulls++
+			continue
+		}
+		assert.True(t, time.Time(nt).Year() >= 1970 && time.Time(nt).Year() < 2100)
+		RoundTripJSON(t, nt)
+	}
+	assert.True(t, nulls > 0 && nulls < 50, "%d", nulls)
+}
+
+func TestRandomJSONRawMessage(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		m := RandomJSONRawMessage(rng, 3)
+		require.True(t, json.Valid(m), "%s", m)
+		depth, err := jsonDepth(m)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, depth, 3)
+		RoundTripJSON(t, m)
+	}
+
+	m := RandomJSONRawMessage(rand.New(rand.NewSource(1)), 0)
+	require.True(t, json.Valid(m))
+	assert.NotContains(t, string(m), "[")
+	assert.NotContains(t, string(m), "{")
+}
+
+func TestRoundTripJSON(t *testing.T) {
+	RoundTripJSON(t, types.NullString("foo"))
+	RoundTripJSON(t, types.NullInt64{Int64: 42, Valid: true})
+	RoundTripJSON(t, types.NullJSONRawMessage(`{"a":1}`))
+	RoundTripJSON(t, struct {
+		CreatedAt types.NullTime
+	}{CreatedAt: types.NullTime(time.Now())})
+
+	mock := &recordingTB{TB: t}
+	// jsonLossy loses its value when encoded, so the round trip must fail.
+	RoundTripJSON(mock, jsonLossy{Value: "foo"})
+	require.Len(t, mock.errors, 1)
+	assert.Contains(t, mock.errors[0], "changed the value")
+
+	for _, v := range []interface{}{nil, (*types.NullTime)(nil)} {
+		mock := &recordingTB{TB: t}
+		RoundTripJSON(mock, v)
+		require.Len(t, mock.errors, 1)
+		assert.Contains(t, mock.errors[0], "called with nil")
+	}
+	RoundTripJSON(t, &types.NullInt64{Int64: 1, Valid: true})
+}
+
+func TestAssertJSONEqual(t *testing.T) {
+	AssertJSONEqual(t, types.JSONRawMessage(`{"b": [1, 2.0], "a": {"c": null}}`), `{"a":{"c":null},"b":[1.0,2]}`)
+	AssertJSONEqual(t, types.JSONRawMessage(nil), `null`)
+
+	for _, tc := range []struct {
+		got, want string
+	}{
+		{got: `{"a":1}`, want: `{"a":2}`},
+		{got: `{"a":1}`, want: `{"a":1,"b":2}`},
+		{got: `[1,2]`, want: `[2,1]`},
+		{got: `"1"`, want: `1`},
+	} {
+		mock := &recordingTB{TB: t}
+		AssertJSONEqual(mock, types.JSONRawMessage(tc.got), tc.want)
+		require.Len(t, mock.errors, 1, "%s", tc.got)
+		assert.Contains(t, mock.errors[0], "got:  "+tc.got)
+		assert.Contains(t, mock.errors[0], "want: "+tc.want)
+	}
+
+	mock := &recordingTB{TB: t}
+	AssertJSONEqual(mock, types.JSONRawMessage(`{`), `{}`)
+	AssertJSONEqual(mock, types.JSONRawMessage(`{}`), `{}{}`)
+	require.Len(t, mock.errors, 2)
+	assert.Contains(t, mock.errors[0], "got invalid JSON")
+	assert.Contains(t, mock.errors[1], "want invalid JSON")
+}
+
+// recordingTB records errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+type jsonLossy struct {
+	Value string `json:"-"`
+}
+
+// jsonDepth returns the maximum nesting depth of objects and arrays in raw.
+func jsonDepth(raw []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	var depth, max int
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return max, nil
+		} else if err != nil {
+			return 0, err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
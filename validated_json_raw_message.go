@@ -0,0 +1,85 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// JSONValidator validates a JSON document. Implementations are typically
+// empty structs used as the type parameter of ValidatedJSONRawMessage.
+type JSONValidator interface {
+	ValidateJSON(data []byte) error
+}
+
+// StrictJSON is a JSONValidator which accepts exactly one complete JSON value,
+// optionally surrounded by whitespace. JSONRawMessage.UnmarshalJSON copies its
+// input as-is, so custom decoding code passing it e.g. {}{} is not rejected;
+// use ValidatedJSONRawMessage[StrictJSON] where that matters.
+type StrictJSON struct{}
+
+// ValidateJSON implements JSONValidator.
+func (StrictJSON) ValidateJSON(data []byte) error {
+	if !json.Valid(data) {
+		return errors.New("not exactly one valid JSON value")
+	}
+	return nil
+}
+
+// ValidatedJSONRawMessage is a JSONRawMessage whose Scan and UnmarshalJSON reject
+// documents for which V.ValidateJSON returns an error. SQL NULL is not validated.
+//
+//	type requireVersion struct{}
+//
+//	func (requireVersion) ValidateJSON(data []byte) error { ... }
+//
+//	type Config struct {
+//		Settings types.ValidatedJSONRawMessage[requireVersion]
+//	}
+type ValidatedJSONRawMessage[V JSONValidator] JSONRawMessage
+
+func (m ValidatedJSONRawMessage[V]) validate(data []byte) error {
+	var v V
+	if err := v.ValidateJSON(data); err != nil {
+		return errors.Wrap(err, "document is invalid")
+	}
+	return nil
+}
+
+// Scan implements the Scanner interface.
+func (m *ValidatedJSONRawMessage[V]) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		*m = nil
+		return nil
+	}
+	data := scanBytes(value)
+	if err := m.validate(data); err != nil {
+		return reportDecodeError(scanError("ValidatedJSONRawMessage", value, err), data)
+	}
+	*m = data
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (m ValidatedJSONRawMessage[V]) Value() (driver.Value, error) {
+	return JSONRawMessage(m).Value()
+}
+
+// MarshalJSON returns m as the JSON encoding of m.
+func (m ValidatedJSONRawMessage[V]) MarshalJSON() ([]byte, error) {
+	return JSONRawMessage(m).MarshalJSON()
+}
+
+// UnmarshalJSON sets *m to a copy of data.
+func (m *ValidatedJSONRawMessage[V]) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("types.ValidatedJSONRawMessage: UnmarshalJSON on nil pointer")
+	}
+	if err := m.validate(data); err != nil {
+		return errors.Wrap(err, "types.ValidatedJSONRawMessage")
+	}
+	*m = append((*m)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requireVersion struct{}
+
+func (requireVersion) ValidateJSON(data []byte) error {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if _, ok := doc["version"]; !ok {
+		return errors.New(`missing "version" key`)
+	}
+	return nil
+}
+
+func TestValidatedJSONRawMessage(t *testing.T) {
+	var actual struct {
+		Doc ValidatedJSONRawMessage[requireVersion]
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"Doc":{"version":1}}`), &actual))
+	assert.Equal(t, `{"version":1}`, string(actual.Doc))
+
+	err := json.Unmarshal([]byte(`{"Doc":{"foo":1}}`), &actual)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing "version" key`)
+
+	var m ValidatedJSONRawMessage[requireVersion]
+	require.NoError(t, m.Scan([]byte(`{"version":2}`)))
+	assert.Equal(t, `{"version":2}`, string(m))
+	require.Error(t, m.Scan(`{}`))
+	assert.Equal(t, `{"version":2}`, string(m))
+	require.NoError(t, m.Scan(nil))
+	assert.Empty(t, m)
+}
+
+func TestStrictJSON(t *testing.T) {
+	var m ValidatedJSONRawMessage[StrictJSON]
+	require.NoError(t, m.UnmarshalJSON([]byte(` {"a":[1]} `)))
+	assert.Equal(t, ` {"a":[1]} `, string(m))
+
+	for _, in := range []string{`{}{}`, `{} x`, `{`, ``, `1 2`} {
+		err := m.UnmarshalJSON([]byte(in))
+		require.Error(t, err, "%s", in)
+		assert.Contains(t, err.Error(), "not exactly one valid JSON value")
+	}
+	require.Error(t, m.Scan(`[][]`))
+	require.NoError(t, m.Scan(nil))
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// JSONUpgrade migrates a document from one schema version to the next.
+type JSONUpgrade func(doc JSONRawMessage) (JSONRawMessage, error)
+
+// JSONUpgrader migrates JSON objects which carry their schema version in a
+// top-level key. Documents without the key are version 0. The latest version is
+// the one after the highest registered upgrade. It is safe for concurrent use,
+// and upgrades may register further upgrades.
+type JSONUpgrader struct {
+	key string
+
+	mu       sync.RWMutex
+	upgrades map[int]JSONUpgrade
+}
+
+// NewJSONUpgrader returns a JSONUpgrader without upgrades reading the version
+// from key, e.g. "_v".
+func NewJSONUpgrader(key string) *JSONUpgrader {
+	return &JSONUpgrader{key: key, upgrades: map[int]JSONUpgrade{}}
+}
+
+// Register registers fn to migrate documents of version from to version
+// from+1, replacing any previous registration. fn does not need to update the
+// version key.
+func (u *JSONUpgrader) Register(from int, fn JSONUpgrade) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.upgrades[from] = fn
+}
+
+// Version returns the schema version of doc, which must be an object.
+func (u *JSONUpgrader) Version(doc JSONRawMessage) (int, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc.orNull(), &fields); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if fields == nil {
+		return 0, errors.New("types.JSONUpgrader: Version on a value which is not an object")
+	}
+	v, ok := fields[u.key]
+	if !ok {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, errors.Errorf("types.JSONUpgrader: version %s is not an integer", v)
+	}
+	return version, nil
+}
+
+// Upgrade applies the registered upgrades to doc in sequence, starting at its
+// version, and returns the document at the latest version. Empty documents and
+// null are returned as-is.
+func (u *JSONUpgrader) Upgrade(doc JSONRawMessage) (JSONRawMessage, error) {
+	if isJSONNull(doc) {
+		return doc, nil
+	}
+	version, err := u.Version(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the chain so that upgrades run without holding the lock.
+	var chain []JSONUpgrade
+	u.mu.RLock()
+	for fn, ok := u.upgrades[version]; ok; fn, ok = u.upgrades[version+len(chain)] {
+		chain = append(chain, fn)
+	}
+	u.mu.RUnlock()
+
+	for _, fn := range chain {
+		upgraded, err := fn(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to upgrade from version %d", version)
+		}
+		version++
+		if doc, err = u.setVersion(upgraded, version); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func (u *JSONUpgrader) setVersion(doc JSONRawMessage, version int) (JSONRawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, errors.Wrapf(err, "upgrade to version %d did not return an object", version)
+	}
+	if fields == nil {
+		return nil, errors.Errorf("upgrade to version %d did not return an object", version)
+	}
+	fields[u.key] = json.RawMessage(strconv.Itoa(version))
+	return encodeJSON(fields)
+}
+
+// JSONSchema provides the JSONUpgrader of a VersionedJSONRawMessage.
+// Implementations are typically empty structs used as its type parameter.
+type JSONSchema interface {
+	JSONUpgrader() *JSONUpgrader
+}
+
+// VersionedJSONRawMessage is a JSONRawMessage object carrying its schema
+// version. Scan and UnmarshalJSON apply the upgrades of the JSONUpgrader
+// provided by S, so that the document is at the latest version before it is
+// decoded further. SQL NULL and JSON null are not migrated.
+//
+//	var settingsUpgrader = types.NewJSONUpgrader("_v")
+//
+//	type settingsSchema struct{}
+//
+//	func (settingsSchema) JSONUpgrader() *types.JSONUpgrader { return settingsUpgrader }
+//
+//	type Account struct {
+//		Settings types.VersionedJSONRawMessage[settingsSchema]
+//	}
+type VersionedJSONRawMessage[S JSONSchema] JSONRawMessage
+
+func (m VersionedJSONRawMessage[S]) upgrader() *JSONUpgrader {
+	var s S
+	return s.JSONUpgrader()
+}
+
+// Version returns the schema version of m. As Scan and UnmarshalJSON reject
+// documents whose version is not an integer, it returns 0 only if m has no
+// version, is not an object, or was not decoded by them.
+func (m VersionedJSONRawMessage[S]) Version() int {
+	version, _ := m.upgrader().Version(JSONRawMessage(m))
+	return version
+}
+
+// Scan implements the Scanner interface.
+func (m *VersionedJSONRawMessage[S]) Scan(value interface{}) error {
+	value = unwrapSQLNull(value)
+	if IsDriverNull(value) {
+		value = jsonNull
+	}
+	raw := scanBytes(value)
+	upgraded, err := m.upgrader().Upgrade(raw)
+	if err != nil {
+		return reportDecodeError(scanError("VersionedJSONRawMessage", value, err), raw)
+	}
+	*m = VersionedJSONRawMessage[S](upgraded)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (m VersionedJSONRawMessage[S]) Value() (driver.Value, error) {
+	return JSONRawMessage(m).Value()
+}
+
+// MarshalJSON returns m as the JSON encoding of m.
+func (m VersionedJSONRawMessage[S]) MarshalJSON() ([]byte, error) {
+	return JSONRawMessage(m).MarshalJSON()
+}
+
+// UnmarshalJSON sets *m to a copy of data upgraded to the latest version.
+func (m *VersionedJSONRawMessage[S]) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("types.VersionedJSONRawMessage: UnmarshalJSON on nil pointer")
+	}
+	upgraded, err := m.upgrader().Upgrade(append([]byte{}, data...))
+	if err != nil {
+		return errors.Wrap(err, "types.VersionedJSONRawMessage")
+	}
+	*m = VersionedJSONRawMessage[S](upgraded)
+	return nil
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTitleUpgrader  = NewJSONUpgrader("_v")
+	testSchemaUpgrader = NewJSONUpgrader("schema")
+)
+
+type testTitleSchema struct{}
+
+func (testTitleSchema) JSONUpgrader() *JSONUpgrader { return testTitleUpgrader }
+
+type testSchemaSchema struct{}
+
+func (testSchemaSchema) JSONUpgrader() *JSONUpgrader { return testSchemaUpgrader }
+
+func init() {
+	// v1 stores "name", v2 renames it to "title", and v3 nests it in "meta".
+	testTitleUpgrader.Register(1, func(doc JSONRawMessage) (JSONRawMessage, error) {
+		var v map[string]interface{}
+		if err := json.Unmarshal(doc, &v); err != nil {
+			return nil, err
+		}
+		v["title"] = v["name"]
+		delete(v, "name")
+		return json.Marshal(v)
+	})
+	testTitleUpgrader.Register(2, func(doc JSONRawMessage) (JSONRawMessage, error) {
+		var v map[string]interface{}
+		if err := json.Unmarshal(doc, &v); err != nil {
+			return nil, err
+		}
+		if v["title"] == "fail" {
+			return nil, errors.New("cannot upgrade")
+		}
+		v["meta"] = map[string]interface{}{"title": v["title"]}
+		delete(v, "title")
+		return json.Marshal(v)
+	})
+
+	testSchemaUpgrader.Register(7, func(doc JSONRawMessage) (JSONRawMessage, error) {
+		return doc.RenameKey("old", "new")
+	})
+}
+
+func TestVersionedJSONRawMessage(t *testing.T) {
+	var m VersionedJSONRawMessage[testTitleSchema]
+	require.NoError(t, m.Scan([]byte(`{"_v":1,"name":"a"}`)))
+	assert.JSONEq(t, `{"_v":3,"meta":{"title":"a"}}`, string(m))
+	assert.Equal(t, 3, m.Version())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"_v":2,"title":"b"}`), &m))
+	assert.JSONEq(t, `{"_v":3,"meta":{"title":"b"}}`, string(m))
+
+	require.NoError(t, m.Scan(`{"_v":3,"meta":{}}`))
+	assert.Equal(t, `{"_v":3,"meta":{}}`, string(m))
+
+	require.NoError(t, m.Scan(nil))
+	assert.Equal(t, `null`, string(m))
+	assert.Equal(t, 0, m.Version())
+
+	err := m.Scan(`{"_v":1,"name":"fail"}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version 2")
+
+	require.Error(t, m.Scan(`{"_v":"x"}`))
+	require.Error(t, m.Scan(`[1]`))
+	assert.Equal(t, 0, VersionedJSONRawMessage[testTitleSchema](`{"_v":"x"}`).Version())
+}
+
+func TestVersionedJSONRawMessageSeparateUpgraders(t *testing.T) {
+	var titled VersionedJSONRawMessage[testTitleSchema]
+	var schema VersionedJSONRawMessage[testSchemaSchema]
+
+	require.NoError(t, titled.Scan(`{"_v":2,"schema":7,"title":"a"}`))
+	assert.JSONEq(t, `{"_v":3,"schema":7,"meta":{"title":"a"}}`, string(titled))
+
+	require.NoError(t, schema.Scan(`{"_v":2,"schema":7,"old":1}`))
+	assert.JSONEq(t, `{"_v":2,"schema":8,"new":1}`, string(schema))
+	assert.Equal(t, 8, schema.Version())
+
+	require.NoError(t, schema.Scan(`{"_v":1}`))
+	assert.Equal(t, 0, schema.Version())
+}
+
+func TestJSONUpgraderRegisterDuringUpgrade(t *testing.T) {
+	u := NewJSONUpgrader("v")
+	u.Register(0, func(doc JSONRawMessage) (JSONRawMessage, error) {
+		// Registering from within an upgrade must not deadlock. The new upgrade
+		// is picked up by the next call to Upgrade.
+		u.Register(1, func(doc JSONRawMessage) (JSONRawMessage, error) { return doc, nil })
+		return doc, nil
+	})
+
+	out, err := u.Upgrade(JSONRawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":1}`, string(out))
+
+	out, err = u.Upgrade(JSONRawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":2}`, string(out))
+
+	version, err := u.Version(JSONRawMessage(`{"v":5}`))
+	require.NoError(t, err)
+	assert.Equal(t, 5, version)
+	_, err = u.Version(JSONRawMessage(`[]`))
+	require.Error(t, err)
+}